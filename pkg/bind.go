@@ -0,0 +1,194 @@
+package dotenv
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTarget is returned by Bind when it is not given a non-nil pointer to a struct.
+var ErrInvalidTarget = errors.New("target must be a non-nil pointer to a struct")
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	urlType             = reflect.TypeOf(url.URL{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind populates the fields of the struct target points to from the key value pairs,
+// following the tags of the fields:
+//
+//	Port    int           `env:"PORT" default:"8080"`
+//	Token   string        `env:"TOKEN" required:"true"`
+//	Hosts   []string      `env:"HOSTS" separator:";"`
+//	Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+//	DB      Database      `prefix:"DB_"`
+//
+// A missing key takes the default value when there is one, and fails when the field is required.
+// A key set to an empty value is not missing, so KEY= binds the empty value,
+// or a nil pointer for pointer fields as Marshal writes them. Fields without env tag are skipped,
+// except structs which are bound with their prefix added to the keys of their fields.
+// Strings, bools, numbers, time.Duration, *url.URL, encoding.TextUnmarshaler types,
+// slices of them split on separator (a comma by default) and pointers to them are supported.
+// All the failures are returned joined with errors.Join.
+func (env *EnvContent) Bind(target any) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+
+	return errors.Join(env.bindStruct(value.Elem(), "")...)
+}
+
+func (env *EnvContent) bindStruct(value reflect.Value, prefix string) []error {
+	var errs []error
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key, tagged := field.Tag.Lookup("env")
+		if key == "-" {
+			continue
+		}
+
+		if !tagged {
+			if isNestedStruct(field.Type) {
+				nested := value.Field(i)
+				if nested.Kind() == reflect.Pointer {
+					if nested.IsNil() {
+						nested.Set(reflect.New(field.Type.Elem()))
+					}
+					nested = nested.Elem()
+				}
+				errs = append(errs, env.bindStruct(nested, prefix+field.Tag.Get("prefix"))...)
+			}
+			continue
+		}
+
+		key = prefix + key
+		raw, found := env.Lookup(key)
+		if !found {
+			defaultValue, hasDefault := field.Tag.Lookup("default")
+			if !hasDefault {
+				if required, _ := strconv.ParseBool(field.Tag.Get("required")); required {
+					errs = append(errs, fmt.Errorf("%w: %s is required", ErrMissingValue, key))
+				}
+				continue
+			}
+			raw = defaultValue
+		}
+
+		separator := field.Tag.Get("separator")
+		if separator == "" {
+			separator = ","
+		}
+
+		if err := setField(value.Field(i), raw, separator); err != nil {
+			errs = append(errs, newValueError(key, raw, field.Type.String(), err))
+		}
+	}
+
+	return errs
+}
+
+// isNestedStruct reports whether a field holds a struct whose fields are bound one by one.
+func isNestedStruct(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType.Kind() == reflect.Struct &&
+		fieldType != urlType &&
+		!reflect.PointerTo(fieldType).Implements(textUnmarshalerType)
+}
+
+// setField converts raw to the type of value and stores it.
+func setField(value reflect.Value, raw string, separator string) error {
+	if value.Kind() == reflect.Pointer {
+		if raw == "" {
+			value.SetZero()
+			return nil
+		}
+		if value.Type().Elem() == urlType {
+			parsed, err := parseURL(strings.TrimSpace(raw))
+			if err != nil {
+				return err
+			}
+			value.Set(reflect.ValueOf(parsed))
+			return nil
+		}
+
+		element := reflect.New(value.Type().Elem())
+		if err := setField(element.Elem(), raw, separator); err != nil {
+			return err
+		}
+		value.Set(element)
+		return nil
+	}
+
+	if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(raw))
+	}
+
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := parseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return err
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(raw), 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(strings.TrimSpace(raw), 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(raw), value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(parsed)
+	case reflect.Slice:
+		items, err := splitList(raw, separator)
+		if err != nil {
+			return err
+		}
+
+		slice := reflect.MakeSlice(value.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setField(slice.Index(i), item, separator); err != nil {
+				return fmt.Errorf("item %d: %w", i, numErrorReason(err))
+			}
+		}
+		value.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package dotenv
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type logLevel int
+
+func (l *logLevel) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+type databaseConfig struct {
+	Host string `env:"HOST" default:"localhost"`
+	Port int    `env:"PORT" default:"5432"`
+}
+
+type cacheConfig struct {
+	Size uint64 `env:"SIZE"`
+}
+
+type bindConfig struct {
+	Name     string        `env:"NAME" required:"true"`
+	Port     uint16        `env:"PORT" default:"8080"`
+	Debug    bool          `env:"DEBUG"`
+	Ratio    float64       `env:"RATIO"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"5s"`
+	Endpoint *url.URL      `env:"ENDPOINT"`
+	Hosts    []string      `env:"HOSTS"`
+	Ports    []int         `env:"PORTS" separator:";"`
+	IP       net.IP        `env:"IP"`
+	Level    logLevel      `env:"LEVEL"`
+	Retries  *int          `env:"RETRIES"`
+	Missing  *int          `env:"MISSING"`
+	Ignored  string        `env:"-"`
+	Untagged string
+	Database databaseConfig `prefix:"DB_"`
+	Cache    *cacheConfig   `prefix:"CACHE_"`
+}
+
+type BindTestCase struct {
+	desc           string
+	input          string
+	expectedError  error
+	expectedConfig bindConfig
+}
+
+func TestENV_Bind(t *testing.T) {
+	retries := 3
+	endpoint, _ := url.Parse("https://api.example.com/v1")
+
+	testCases := []BindTestCase{
+		{
+			desc: "Every kind of field",
+			input: "NAME=api\n" +
+				"PORT=9090\n" +
+				"DEBUG=on\n" +
+				"RATIO=0.5\n" +
+				"TIMEOUT=1m\n" +
+				"ENDPOINT=https://api.example.com/v1\n" +
+				"HOSTS=a.com, b.com\n" +
+				"PORTS=80;443\n" +
+				"IP=10.0.0.1\n" +
+				"LEVEL=info\n" +
+				"RETRIES=3\n" +
+				"Ignored=value\n" +
+				"Untagged=value\n" +
+				"DB_HOST=db.internal\n" +
+				"CACHE_SIZE=1024",
+			expectedError: nil,
+			expectedConfig: bindConfig{
+				Name:     "api",
+				Port:     9090,
+				Debug:    true,
+				Ratio:    0.5,
+				Timeout:  time.Minute,
+				Endpoint: endpoint,
+				Hosts:    []string{"a.com", "b.com"},
+				Ports:    []int{80, 443},
+				IP:       net.ParseIP("10.0.0.1"),
+				Level:    1,
+				Retries:  &retries,
+				Database: databaseConfig{Host: "db.internal", Port: 5432},
+				Cache:    &cacheConfig{Size: 1024},
+			},
+		},
+		{
+			desc:          "Defaults",
+			input:         "NAME=api",
+			expectedError: nil,
+			expectedConfig: bindConfig{
+				Name:     "api",
+				Port:     8080,
+				Timeout:  5 * time.Second,
+				Database: databaseConfig{Host: "localhost", Port: 5432},
+				Cache:    &cacheConfig{},
+			},
+		},
+		{
+			desc:          "Missing required key",
+			input:         "PORT=9090",
+			expectedError: ErrMissingValue,
+		},
+		{
+			desc:          "Invalid number",
+			input:         "NAME=api\nPORT=70000",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Invalid slice item",
+			input:         "NAME=api\nPORTS=80;http",
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Invalid text",
+			input:         "NAME=api\nLEVEL=loud",
+			expectedError: ErrInvalidValue,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			parser := EnvContent{}
+			_, _ = parser.LoadFromString(test.input)
+
+			var config bindConfig
+			resultedError := parser.Bind(&config)
+
+			assert.ErrorIs(t, resultedError, test.expectedError)
+			if test.expectedError == nil {
+				assert.Equal(t, test.expectedConfig, config)
+			}
+		})
+	}
+}
+
+func TestENV_BindErrors(t *testing.T) {
+	parser := EnvContent{}
+	_, _ = parser.LoadFromString("PORT=http\nRATIO=half\nDB_PORT=x")
+
+	var config bindConfig
+	err := parser.Bind(&config)
+
+	assert.ErrorIs(t, err, ErrMissingValue)
+	assert.Equal(t, "value for the given key is not found: NAME is required\n"+
+		`PORT="http" is not a valid uint16: invalid syntax`+"\n"+
+		`RATIO="half" is not a valid float64: invalid syntax`+"\n"+
+		`DB_PORT="x" is not a valid int: invalid syntax`, err.Error())
+}
+
+func TestENV_BindInvalidTarget(t *testing.T) {
+	parser := EnvContent{}
+	var config bindConfig
+
+	assert.ErrorIs(t, parser.Bind(config), ErrInvalidTarget)
+	assert.ErrorIs(t, parser.Bind((*bindConfig)(nil)), ErrInvalidTarget)
+	assert.ErrorIs(t, parser.Bind(new(int)), ErrInvalidTarget)
+}
+
+type proxyConfig struct {
+	Proxy string `env:"HTTP_PROXY" default:"http://proxy:3128"`
+	Token string `env:"TOKEN" required:"true"`
+	Port  int    `env:"PORT" default:"8080"`
+}
+
+func TestENV_BindEmptyValue(t *testing.T) {
+	parser := EnvContent{}
+	_, _ = parser.LoadFromString("HTTP_PROXY=\nTOKEN=")
+
+	config := proxyConfig{}
+	assert.Equal(t, nil, parser.Bind(&config))
+	assert.Equal(t, proxyConfig{Proxy: "", Token: "", Port: 8080}, config)
+
+	_, _ = parser.LoadFromString("TOKEN=secret\nPORT=")
+
+	config = proxyConfig{}
+	err := parser.Bind(&config)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.Equal(t, "http://proxy:3128", config.Proxy)
+}
+
+func TestENV_BindEmptyPointer(t *testing.T) {
+	retries := 3
+	parser := EnvContent{}
+	_, _ = parser.LoadFromString("NAME=api\nENDPOINT=\nRETRIES=")
+
+	config := bindConfig{Retries: &retries}
+	assert.Equal(t, nil, parser.Bind(&config))
+	assert.Nil(t, config.Endpoint)
+	assert.Nil(t, config.Retries)
+}
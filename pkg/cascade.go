@@ -0,0 +1,53 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// DefaultModeVariable is the environment variable LoadCascade reads the mode from
+// when neither the mode nor ModeVariable are given.
+const DefaultModeVariable = "APP_ENV"
+
+// CascadeFiles returns the conventional .env files of a mode in dir,
+// from the lowest to the highest precedence:
+// .env, .env.<mode>, .env.local and .env.<mode>.local.
+// .env.local is left out in test mode so tests get the same results on every machine.
+func CascadeFiles(dir string, mode string) []string {
+	fileNames := []string{".env"}
+	if mode != "" {
+		fileNames = append(fileNames, ".env."+mode)
+	}
+	if mode != "test" {
+		fileNames = append(fileNames, ".env.local")
+	}
+	if mode != "" {
+		fileNames = append(fileNames, ".env."+mode+".local")
+	}
+
+	for i, fileName := range fileNames {
+		fileNames[i] = filepath.Join(dir, fileName)
+	}
+	return fileNames
+}
+
+// LoadCascade loads the files returned by CascadeFiles that exist in dir, so keys of
+// higher precedence files win. When mode is empty it is read from the ModeVariable
+// environment variable.
+func (env *EnvContent) LoadCascade(dir string, mode string) (map[string]string, error) {
+	if mode == "" {
+		variable := env.ModeVariable
+		if variable == "" {
+			variable = DefaultModeVariable
+		}
+		mode = os.Getenv(variable)
+	}
+
+	fileNames := CascadeFiles(dir, mode)
+	if env.Precedence == FirstWins {
+		slices.Reverse(fileNames)
+	}
+
+	return env.loadFromFiles(fileNames, true)
+}
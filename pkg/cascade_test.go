@@ -0,0 +1,123 @@
+package dotenv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type LoadCascadeTestCase struct {
+	desc          string
+	mode          string
+	modeVariable  string
+	environment   map[string]string
+	precedence    Precedence
+	expectedError error
+	expectedMap   map[string]string
+}
+
+func TestENV_CascadeFiles(t *testing.T) {
+	assert.Equal(t, []string{
+		"config/.env",
+		"config/.env.production",
+		"config/.env.local",
+		"config/.env.production.local",
+	}, CascadeFiles("config", "production"))
+	assert.Equal(t, []string{".env", ".env.test", ".env.test.local"}, CascadeFiles("", "test"))
+	assert.Equal(t, []string{".env", ".env.local"}, CascadeFiles("", ""))
+}
+
+func TestENV_LoadCascade(t *testing.T) {
+	testCases := []LoadCascadeTestCase{
+		{
+			desc:          "Development mode",
+			mode:          "development",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"NAME":  "base",
+				"LEVEL": "development.local",
+				"LOCAL": "yes",
+				"DEV":   "yes",
+			},
+		},
+		{
+			desc:          "Development mode with first file wins",
+			mode:          "development",
+			precedence:    FirstWins,
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"NAME":  "base",
+				"LEVEL": "development.local",
+				"LOCAL": "yes",
+				"DEV":   "yes",
+			},
+		},
+		{
+			desc:          "Test mode leaves .env.local out",
+			mode:          "test",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"NAME":  "base",
+				"LEVEL": "test",
+			},
+		},
+		{
+			desc:          "Mode without files",
+			mode:          "production",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"NAME":  "base",
+				"LEVEL": "local",
+				"LOCAL": "yes",
+			},
+		},
+		{
+			desc:          "Mode read from APP_ENV",
+			environment:   map[string]string{"APP_ENV": "test"},
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"NAME":  "base",
+				"LEVEL": "test",
+			},
+		},
+		{
+			desc:          "Mode read from a custom variable",
+			modeVariable:  "DOTENV_TEST_MODE",
+			environment:   map[string]string{"APP_ENV": "test", "DOTENV_TEST_MODE": "development"},
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"NAME":  "base",
+				"LEVEL": "development.local",
+				"LOCAL": "yes",
+				"DEV":   "yes",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv("APP_ENV", "")
+			for key, value := range test.environment {
+				t.Setenv(key, value)
+			}
+
+			parser := EnvContent{Precedence: test.precedence, ModeVariable: test.modeVariable}
+			resultedMap, resultedError := parser.LoadCascade("testdata/cascade", test.mode)
+
+			assert.ErrorIs(t, resultedError, test.expectedError)
+			assert.False(t, parser.SkipMissing)
+			if !reflect.DeepEqual(test.expectedMap, resultedMap) {
+				t.Fail()
+			}
+
+		})
+	}
+}
+
+func TestENV_LoadCascadeMissingDirectory(t *testing.T) {
+	parser := EnvContent{}
+	_, err := parser.LoadCascade("testdata/missing", "development")
+
+	assert.ErrorIs(t, err, ErrFileIsEmpty)
+}
@@ -0,0 +1,88 @@
+package dotenv
+
+import (
+	"bytes"
+	"strings"
+)
+
+// node is a line of a loaded document, or the lines of a multi-line value.
+type node struct {
+	text string
+	// key is empty for blank lines, comments and lines that can not be parsed.
+	key string
+	// stored is set when the value of the line is the one key was loaded with,
+	// it is not for duplicates that lost to another line.
+	stored bool
+	// value is the value key was loaded with, the line is rewritten only when it changes.
+	value string
+	// expanded is set when value was expanded from references to other keys.
+	expanded bool
+	// removed is set once key is deleted, the line is no longer written.
+	removed bool
+	// valueStart and valueEnd bound the value in text, so the key, the separator,
+	// the spacing and the inline comment around it are kept when it is rewritten.
+	valueStart int
+	valueEnd   int
+}
+
+// writeDocument writes the loaded document with the lines of the changed keys rewritten,
+// and returns the keys it holds.
+// Once any key changed, the references of the other lines could expand to different values,
+// so expanded values are written as they are instead of their references.
+func (env *EnvContent) writeDocument(document *bytes.Buffer) map[string]bool {
+	written := make(map[string]bool)
+
+	// a key defined several times takes the value of its last line
+	last := make(map[string]*node)
+	for _, current := range env.document {
+		if current.stored {
+			last[current.key] = current
+		}
+	}
+
+	changed := false
+	for key, current := range last {
+		value, found := env.keyValuePairs[key]
+		changed = changed || current.removed || !found || value != current.value
+	}
+	for key := range env.keyValuePairs {
+		changed = changed || last[key] == nil
+	}
+
+	lines := make([]string, 0, len(env.document))
+	for _, current := range env.document {
+		if current.removed {
+			continue
+		}
+
+		text := current.text
+		if current.stored && last[current.key] == current {
+			if value, found := env.keyValuePairs[current.key]; found && (value != current.value || (changed && current.expanded)) {
+				text = text[:current.valueStart] + quoteValue(value) + text[current.valueEnd:]
+			}
+			written[current.key] = true
+		}
+		lines = append(lines, strings.ReplaceAll(text, "\n", env.lineEnding()))
+	}
+
+	document.WriteString(strings.Join(lines, env.lineEnding()))
+	return written
+}
+
+// lineEnding returns the line ending of the loaded document, "\n" when nothing was loaded.
+func (env *EnvContent) lineEnding() string {
+	if env.newline == "" {
+		return "\n"
+	}
+	return env.newline
+}
+
+// deleteLines removes the lines defining key from the loaded document,
+// a blank line or a comment right above them stays.
+func (env *EnvContent) deleteLines(key string) {
+	for _, current := range env.document {
+		if current.key == key {
+			current.removed = true
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package dotenv
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type DocumentTestCase struct {
+	desc  string
+	input string
+	// set holds the keys to set followed by their values.
+	set              []string
+	expectedDocument string
+}
+
+func TestENV_WriteToDocument(t *testing.T) {
+	testCases := []DocumentTestCase{
+		{
+			desc: "Unchanged document",
+			input: "# database settings\n" +
+				"\n" +
+				"export DB_HOST = localhost   # local only\n" +
+				"DB_PORT: 5432\n" +
+				"DB_URL=\"postgres://${DB_HOST}:${DB_PORT}\"\n",
+			set: nil,
+			expectedDocument: "# database settings\n" +
+				"\n" +
+				"export DB_HOST = localhost   # local only\n" +
+				"DB_PORT: 5432\n" +
+				"DB_URL=\"postgres://${DB_HOST}:${DB_PORT}\"\n",
+		},
+		{
+			desc: "Changed values keep separator, spacing and comments",
+			input: "# database settings\n" +
+				"\n" +
+				"export DB_HOST = localhost   # local only\n" +
+				"DB_PORT: 5432\n" +
+				"  DB_NAME='app'\n",
+			set: []string{"DB_HOST", "db.internal", "DB_PORT", "6543", "DB_NAME", "app test"},
+			expectedDocument: "# database settings\n" +
+				"\n" +
+				"export DB_HOST = db.internal   # local only\n" +
+				"DB_PORT: 6543\n" +
+				"  DB_NAME='app test'\n",
+		},
+		{
+			desc: "Empty value",
+			input: "KEY=\n" +
+				"OTHER= # comment\n",
+			set: []string{"KEY", "value", "OTHER", "value"},
+			expectedDocument: "KEY=value\n" +
+				"OTHER=value # comment\n",
+		},
+		{
+			desc: "Multi-line value",
+			input: "# certificate\n" +
+				"TLS_KEY=\"first\n" +
+				"second\"\n" +
+				"TLS_ENABLED=true\n",
+			set: []string{"TLS_KEY", "changed"},
+			expectedDocument: "# certificate\n" +
+				"TLS_KEY=changed\n" +
+				"TLS_ENABLED=true\n",
+		},
+		{
+			desc: "CRLF line endings",
+			input: "# certificate\r\n" +
+				"TLS_KEY=\"first\r\n" +
+				"second\"\r\n" +
+				"\r\n" +
+				"TLS_ENABLED=true # toggle\r\n",
+			set: []string{"TLS_ENABLED", "false", "NEW", "value"},
+			expectedDocument: "# certificate\r\n" +
+				"TLS_KEY=\"first\r\n" +
+				"second\"\r\n" +
+				"\r\n" +
+				"TLS_ENABLED=false # toggle\r\n" +
+				"NEW=value\r\n",
+		},
+		{
+			desc: "Expanded values are written once a key changes",
+			input: "A=1\n" +
+				"B=${A}x\n" +
+				"C=\"$A and \\$HOME\" # note\n" +
+				"D='${A}'\n",
+			set: []string{"A", "2"},
+			expectedDocument: "A=2\n" +
+				"B=1x\n" +
+				"C='1 and $HOME' # note\n" +
+				"D='${A}'\n",
+		},
+		{
+			desc: "Expanded values are written once a key is added",
+			input: "A=1\n" +
+				"B=${A}x\n",
+			set: []string{"C", "3"},
+			expectedDocument: "A=1\n" +
+				"B=1x\n" +
+				"C=3\n",
+		},
+		{
+			desc: "Duplicate key rewrites the line that won",
+			input: "KEY=first\n" +
+				"KEY=second\n",
+			set: []string{"KEY", "third"},
+			expectedDocument: "KEY=first\n" +
+				"KEY=third\n",
+		},
+		{
+			desc:  "New keys are appended",
+			input: "# settings\nKEY=value",
+			set:   []string{"NEW_B", "b", "NEW_A", "a b"},
+			expectedDocument: "# settings\n" +
+				"KEY=value\n" +
+				"NEW_B=b\n" +
+				"NEW_A='a b'\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			parser := EnvContent{}
+			_, err := parser.LoadFromString(test.input)
+			assert.Equal(t, nil, err)
+
+			expectedMap := make(map[string]string)
+			for key, value := range parser.keyValuePairs {
+				expectedMap[key] = value
+			}
+			for i := 0; i < len(test.set); i += 2 {
+				parser.Set(test.set[i], test.set[i+1])
+				expectedMap[test.set[i]] = test.set[i+1]
+			}
+
+			var document bytes.Buffer
+			_, err = parser.WriteTo(&document)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedDocument, document.String())
+
+			loaded := EnvContent{}
+			resultedMap, _ := loaded.LoadFromString(document.String())
+			assert.Equal(t, expectedMap, resultedMap)
+		})
+	}
+}
+
+func TestENV_WriteToDocumentAfterFiles(t *testing.T) {
+	parser := EnvContent{}
+	_, err := parser.LoadFromFiles([]string{"testdata/test_01.txt", "testdata/test_21.txt"})
+	assert.Equal(t, nil, err)
+
+	var document bytes.Buffer
+	_, err = parser.WriteTo(&document)
+	assert.Equal(t, nil, err)
+
+	loaded := EnvContent{}
+	resultedMap, _ := loaded.LoadFromString(document.String())
+	assert.Equal(t, parser.keyValuePairs, resultedMap)
+}
+
+func TestENV_DeleteFromDocument(t *testing.T) {
+	parser := EnvContent{}
+	_, err := parser.LoadFromString("# settings\n" +
+		"KEY=first\n" +
+		"\n" +
+		"# old key\n" +
+		"OLD=value\n" +
+		"KEY=second\n" +
+		"LAST=value")
+	assert.Equal(t, nil, err)
+
+	assert.True(t, parser.Delete("KEY"))
+	assert.True(t, parser.Delete("LAST"))
+
+	var document bytes.Buffer
+	_, err = parser.WriteTo(&document)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "# settings\n"+
+		"\n"+
+		"# old key\n"+
+		"OLD=value", document.String())
+}
+
+func TestENV_DeleteFromDocumentFirstWins(t *testing.T) {
+	parser := EnvContent{Precedence: FirstWins}
+	_, err := parser.LoadFromString("KEY=first\nKEY=second\nOTHER=value\n")
+	assert.Equal(t, nil, err)
+
+	assert.True(t, parser.Delete("KEY"))
+
+	var document bytes.Buffer
+	_, err = parser.WriteTo(&document)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "OTHER=value\n", document.String())
+}
+
+func TestENV_WriteToAfterFailedLoad(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), ".env")
+	assert.Equal(t, nil, os.WriteFile(fileName, []byte("A=1\nbad line\nC=3\n"), 0o600))
+
+	parser := EnvContent{}
+	_, err := parser.LoadFromFile(fileName)
+	assert.ErrorIs(t, err, ErrWrongFormat)
+
+	parser.Set("A", "9")
+	assert.Equal(t, nil, parser.SaveToFile(fileName))
+
+	document, err := os.ReadFile(fileName)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "A=9\n", string(document))
+}
+
+func TestENV_DeleteReferencedKey(t *testing.T) {
+	parser := EnvContent{}
+	_, err := parser.LoadFromString("A=1\nB=${A}x\n")
+	assert.Equal(t, nil, err)
+
+	assert.True(t, parser.Delete("A"))
+
+	var document bytes.Buffer
+	_, err = parser.WriteTo(&document)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "B=1x\n", document.String())
+}
@@ -4,67 +4,218 @@ package dotenv
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"slices"
 	"strings"
+	"unicode"
 )
 
+// Errors returned by the methods of EnvContent, they can be matched with errors.Is.
 var (
-	errReadingFile   = errors.New("can not read file")
-	errFileIsEmpty   = errors.New(".env is empty or does not have key value pairs")
-	errWrongFormat   = errors.New(".env is not in correct format")
-	errAlreadyExists = errors.New("key value pair already exists")
-	errMissingValue  = errors.New("value for the given key is not found")
-	errEmptyMap      = errors.New(" map does not has no key value pairs")
+	ErrReadingFile   = errors.New("can not read file")
+	ErrFileIsEmpty   = errors.New(".env is empty or does not have key value pairs")
+	ErrWrongFormat   = errors.New(".env is not in correct format")
+	ErrAlreadyExists = errors.New("key value pair already exists")
+	ErrMissingValue  = errors.New("value for the given key is not found")
+	ErrEmptyMap      = errors.New(" map does not has no key value pairs")
+	ErrSettingEnv    = errors.New("can not set environment variable")
 )
 
 type EnvContent struct {
 	keyValuePairs map[string]string
+	// sources maps every key to the file it was loaded from.
+	sources   map[string]string
+	overrides []Override
+	// order holds the keys in the order they were first defined.
+	order []string
+	// document holds the lines of the last file or string loaded alone, so it can be written back as it was.
+	document []*node
+	// newline is the line ending of the document, "\r\n" for files written on Windows.
+	newline string
+
+	// DisableExpansion keeps ${VAR} and $VAR references in values as they are.
+	DisableExpansion bool
+
+	// StrictExport reports an error for "export KEY" lines that do not assign a value,
+	// which are skipped otherwise.
+	StrictExport bool
+
+	// Precedence decides which value LoadFromFiles keeps for keys defined in several files.
+	Precedence Precedence
+
+	// ModeVariable is the environment variable LoadCascade reads the mode from,
+	// DefaultModeVariable is used when it is empty.
+	ModeVariable string
+
+	// SkipMissing makes LoadFromFiles skip the files that do not exist,
+	// files that can not be read or parsed still fail.
+	SkipMissing bool
+
+	// Lenient keeps loading after a line that can not be parsed, the pairs that could be
+	// parsed are returned along with all the errors joined with errors.Join.
+	Lenient bool
+
+	// Backup makes SaveToFile keep the previous content of the file in a ".bak" file next to it.
+	Backup bool
 }
 
 // LoadFromString loads the content of .env file from multi-lined string.
 func (env *EnvContent) LoadFromString(envContents string) (map[string]string, error) {
-	env.keyValuePairs = make(map[string]string)
-	return env.loadFromString(envContents)
+	env.reset()
+	return env.loadFromString(envContents, "")
 }
 
-func (env *EnvContent) loadFromString(envContents string) (map[string]string, error) {
+// loadFromString adds the key value pairs of envContents to the map,
+// fileName is only used to locate parse errors.
+func (env *EnvContent) loadFromString(envContents string, fileName string) (map[string]string, error) {
 
-	lines := strings.Split(envContents, "\n")
+	lines := strings.Split(strings.ReplaceAll(envContents, "\r\n", "\n"), "\n")
+	if strings.Contains(envContents, "\r\n") {
+		env.newline = "\r\n"
+	}
+	templates := make(map[string]entry)
+	var errs []error
 
-	for _, line := range lines {
+	for i := 0; i < len(lines); i++ {
 
-		line = strings.TrimSpace(line)
+		line := strings.TrimSpace(lines[i])
 		if len(line) == 0 {
+			env.document = append(env.document, &node{text: lines[i]})
 			continue
 		} else if string(line[0]) == "#" {
+			env.document = append(env.document, &node{text: lines[i]})
 			continue
 		} else {
-			s := strings.Split(line, "=")
+			start := i + 1
+			if isUnterminated(line) {
+				line = strings.TrimLeftFunc(lines[i], unicode.IsSpace)
+				for isUnterminated(line) && i+1 < len(lines) {
+					i++
+					line += "\n" + lines[i]
+				}
+				line = strings.TrimSpace(line)
+			}
+
+			current := &node{text: strings.Join(lines[start-1:i+1], "\n")}
+			env.document = append(env.document, current)
+
+			parsed, err := parseLine(line, !env.DisableExpansion)
+			if err == nil && parsed.bare && env.StrictExport {
+				err = &syntaxError{offset: 0, reason: "export without a value"}
+			}
+			if err != nil {
+				errs = append(errs, newParseError(err, line, lines, start, fileName))
+				if !env.Lenient {
+					// the lines after the error were not read, the document can not be written back
+					env.document = nil
+					return env.keyValuePairs, errs[0]
+				}
+				continue
+			}
+			parsed.line = start
+
+			if parsed.bare {
+				continue
+			}
+			current.key = parsed.key
+
+			stored, err := env.assign(parsed, fileName)
+			if err != nil {
+				errs = append(errs, err)
+				if !env.Lenient {
+					env.document = nil
+					return env.keyValuePairs, err
+				}
+				continue
+			}
+			if !stored {
+				continue
+			}
+
+			lead := len(current.text) - len(strings.TrimLeftFunc(current.text, unicode.IsSpace))
+			current.stored = true
+			current.expanded = parsed.expand
+			current.valueStart = lead + parsed.valueStart
+			current.valueEnd = lead + parsed.valueEnd
 
-			if s[0] == line {
-				s = strings.Split(line, ":")
+			if parsed.expand {
+				templates[parsed.key] = parsed
+			} else {
+				delete(templates, parsed.key)
 			}
-			if s[0] == line || len(s) > 2 {
-				return env.keyValuePairs, errWrongFormat
+		}
+	}
+
+	expander := newExpander(env, fileName, templates)
+	for _, key := range env.Keys() {
+		if _, ok := templates[key]; !ok {
+			continue
+		}
+
+		value, err := expander.resolve(key)
+		if err != nil {
+			if !env.Lenient {
+				env.document = nil
+				return env.keyValuePairs, err
 			}
+			errs = append(errs, err)
+			// the other keys referencing it must not report the same failure again
+			delete(templates, key)
+			delete(env.sources, key)
+			env.remove(key)
+			continue
+		}
+		env.keyValuePairs[key] = value
+	}
 
-			key, value := strings.TrimSpace(s[0]), strings.TrimSpace(s[1])
-			env.keyValuePairs[key] = value
+	for _, current := range env.document {
+		if current.stored {
+			current.value, current.stored = env.keyValuePairs[current.key]
 		}
 	}
 
+	if len(errs) > 0 {
+		return env.keyValuePairs, errors.Join(errs...)
+	}
+
 	emptyMap := make(map[string]string)
 	if fmt.Sprint(emptyMap) == fmt.Sprint(env.keyValuePairs) {
-		return emptyMap, errFileIsEmpty
+		return emptyMap, ErrFileIsEmpty
 	}
 
 	return env.keyValuePairs, nil
 }
 
+// newParseError locates the syntax error found in the entry starting on line start.
+func newParseError(err error, entry string, lines []string, start int, fileName string) error {
+	syntax, ok := err.(*syntaxError)
+	if !ok {
+		return err
+	}
+
+	line := start
+	text := lines[start-1]
+	column := len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace)) + syntax.offset + 1
+
+	if newLine := strings.LastIndexByte(entry[:syntax.offset], '\n'); newLine != -1 {
+		line += strings.Count(entry[:syntax.offset], "\n")
+		column = syntax.offset - newLine
+	}
+
+	return &ParseError{
+		File:   fileName,
+		Line:   line,
+		Column: column,
+		Text:   lines[line-1],
+		Reason: syntax.reason,
+	}
+}
+
 // LoadFromFile loads the content of a given .env file
 func (env *EnvContent) LoadFromFile(fileName string) (map[string]string, error) {
 
-	env.keyValuePairs = make(map[string]string)
+	env.reset()
 	emptyMap := make(map[string]string)
 
 	err := error(nil)
@@ -72,86 +223,286 @@ func (env *EnvContent) LoadFromFile(fileName string) (map[string]string, error)
 	fileContent, err := os.ReadFile(fileName)
 
 	if err != nil {
-		return emptyMap, errReadingFile
+		return emptyMap, fmt.Errorf("%w: %w", ErrReadingFile, err)
 	}
 
-	_, err = env.loadFromString(string(fileContent))
+	_, err = env.loadFromString(string(fileContent), fileName)
 
-	if err != nil {
+	if err != nil && !env.Lenient {
 		return emptyMap, err
 	}
 
-	if fmt.Sprint(emptyMap) == fmt.Sprint(env.keyValuePairs) {
-		return emptyMap, errFileIsEmpty
+	if err == nil && fmt.Sprint(emptyMap) == fmt.Sprint(env.keyValuePairs) {
+		return emptyMap, ErrFileIsEmpty
 	}
 
 	return env.keyValuePairs, err
 }
 
-// LoadFromFiles loads the content of given .env files
+// FileFailure holds the reason a file could not be loaded.
+type FileFailure struct {
+	File string
+	Err  error
+}
+
+// FilesError is returned by LoadFromFiles with every file that could not be loaded.
+type FilesError struct {
+	Failures []FileFailure
+}
+
+func (e *FilesError) Error() string {
+	messages := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		message := failure.Err.Error()
+		if !namesFile(failure.Err) {
+			message = failure.File + ": " + message
+		}
+		messages = append(messages, message)
+	}
+	return strings.Join(messages, "\n")
+}
+
+// namesFile reports whether the message of err already holds the file it comes from.
+func namesFile(err error) bool {
+	var parseError *ParseError
+	var requiredError *RequiredVariableError
+	var expandError *ExpandError
+	var pathError *fs.PathError
+	return (errors.As(err, &parseError) && parseError.File != "") ||
+		(errors.As(err, &requiredError) && requiredError.File != "") ||
+		(errors.As(err, &expandError) && expandError.File != "") ||
+		errors.As(err, &pathError)
+}
+
+// Unwrap allows matching the reason of any failure with errors.Is and errors.As.
+func (e *FilesError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		errs = append(errs, failure.Err)
+	}
+	return errs
+}
+
+// LoadFromFiles loads the content of given .env files, keys defined in several files are resolved by Precedence.
+// All the files are tried and the ones that fail are reported in a FilesError.
 func (env *EnvContent) LoadFromFiles(fileNames []string) (map[string]string, error) {
+	return env.loadFromFiles(fileNames, env.SkipMissing)
+}
+
+// loadFromFiles loads the given files, skipping the ones that do not exist when skipMissing is set.
+func (env *EnvContent) loadFromFiles(fileNames []string, skipMissing bool) (map[string]string, error) {
 
-	env.keyValuePairs = make(map[string]string)
+	env.reset()
 	emptyMap := make(map[string]string)
 
-	err := error(nil)
+	filesError := &FilesError{}
 
 	for _, fileName := range fileNames {
 
 		fileContent, err := os.ReadFile(fileName)
 
 		if err != nil {
-			err = errReadingFile
+			if skipMissing && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			filesError.Failures = append(filesError.Failures, FileFailure{
+				File: fileName,
+				Err:  fmt.Errorf("%w: %w", ErrReadingFile, err),
+			})
 			continue
 		}
 
-		_, err = env.loadFromString(string(fileContent))
+		_, err = env.loadFromString(string(fileContent), fileName)
+
+		if err != nil && !errors.Is(err, ErrFileIsEmpty) {
+			filesError.Failures = append(filesError.Failures, FileFailure{File: fileName, Err: err})
+		}
+	}
+
+	// several files can not be written back as one document
+	env.document = nil
+
+	if len(filesError.Failures) > 0 {
+		if !env.Lenient {
+			return emptyMap, filesError
+		}
+		return env.keyValuePairs, filesError
 	}
 
 	if fmt.Sprint(emptyMap) == fmt.Sprint(env.keyValuePairs) {
-		return emptyMap, errFileIsEmpty
+		return emptyMap, ErrFileIsEmpty
 	}
-	return env.keyValuePairs, err
+	return env.keyValuePairs, nil
 }
 
-// GetEnv retrives the key value pairs of the .env files
+// GetEnv retrieves the key value pairs of the .env files, Keys and Range give them in the order they were defined
 func (env *EnvContent) GetEnv() (map[string]string, error) {
 	emptyMap := make(map[string]string)
 
 	if fmt.Sprint(emptyMap) == fmt.Sprint(env.keyValuePairs) {
-		return emptyMap, errEmptyMap
+		return emptyMap, ErrEmptyMap
 	}
 
 	return env.keyValuePairs, nil
 }
 
-// SetEnv sets the key value pairs to enviroment
-func (env *EnvContent) SetEnv() error {
+// SetEnvReport lists the keys applied to the process environment,
+// the ones skipped because they were already set and the ones the environment rejected.
+type SetEnvReport struct {
+	Applied []string
+	Skipped []string
+	Failed  []string
+
+	// previous holds the values the applied keys had before, nil for unset keys.
+	previous map[string]*string
+}
+
+// Restore puts back the value, or the unset state, every applied key had before.
+func (report SetEnvReport) Restore() error {
+	var errs []error
+
+	for _, key := range report.Applied {
+		var err error
+		if value := report.previous[key]; value != nil {
+			err = os.Setenv(key, *value)
+		} else {
+			err = os.Unsetenv(key)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %q: %w", ErrSettingEnv, key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SetEnv sets the key value pairs to environment, keys already present in the environment keep their value.
+// Keys the environment rejects are reported together in the returned error.
+func (env *EnvContent) SetEnv() (SetEnvReport, error) {
+	return env.setEnv(false)
+}
+
+// OverloadEnv sets the key value pairs to environment, overriding the keys already present
+func (env *EnvContent) OverloadEnv() (SetEnvReport, error) {
+	return env.setEnv(true)
+}
 
+// UnsetEnv removes every loaded key from the environment, whether SetEnv applied it or not.
+// Keys the environment fails to remove are reported together in the returned error.
+func (env *EnvContent) UnsetEnv() error {
 	if env.keyValuePairs == nil {
-		return errEmptyMap
+		return ErrEmptyMap
 	}
 
-	for key, value := range env.keyValuePairs {
-		os.Setenv(key, value)
+	var errs []error
+
+	for _, key := range env.order {
+		if err := os.Unsetenv(key); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %q: %w", ErrSettingEnv, key, err))
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-// Get retrives a value for a specific key from the env map
+func (env *EnvContent) setEnv(overload bool) (SetEnvReport, error) {
+	report := SetEnvReport{previous: make(map[string]*string)}
+
+	if env.keyValuePairs == nil {
+		return report, ErrEmptyMap
+	}
+
+	if len(env.keyValuePairs) == 0 {
+		return report, ErrFileIsEmpty
+	}
+
+	var errs []error
+
+	for _, key := range env.order {
+		previous, found := os.LookupEnv(key)
+		if found && !overload {
+			report.Skipped = append(report.Skipped, key)
+			continue
+		}
+
+		if err := os.Setenv(key, env.keyValuePairs[key]); err != nil {
+			report.Failed = append(report.Failed, key)
+			errs = append(errs, fmt.Errorf("%w: %q: %w", ErrSettingEnv, key, err))
+			continue
+		}
+
+		report.Applied = append(report.Applied, key)
+		if found {
+			report.previous[key] = &previous
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// Keys returns the keys of the env map in the order they were first defined.
+func (env *EnvContent) Keys() []string {
+	return append([]string(nil), env.order...)
+}
+
+// Range calls fn for every key value pair in the order the keys were first defined,
+// until fn returns false.
+func (env *EnvContent) Range(fn func(key, value string) bool) {
+	for _, key := range env.Keys() {
+		if !fn(key, env.keyValuePairs[key]) {
+			return
+		}
+	}
+}
+
+// Get retrieves a value for a specific key from the env map, an empty value is not an error
 func (env *EnvContent) Get(key string) (string, error) {
-	value := env.keyValuePairs[key]
-	if value == "" {
-		return value, errMissingValue
+	value, found := env.Lookup(key)
+	if !found {
+		return value, ErrMissingValue
 	}
 	return value, nil
 }
 
+// Lookup retrieves a value for a specific key from the env map and reports whether the key is present
+func (env *EnvContent) Lookup(key string) (string, bool) {
+	value, found := env.keyValuePairs[key]
+	return value, found
+}
+
 // Set sets a value for a specific key to the env map
 func (env *EnvContent) Set(key string, value string) {
 	if env.keyValuePairs == nil {
 		env.keyValuePairs = make(map[string]string)
 	}
+	env.store(key, value)
+}
+
+// Delete removes a key from the env map, along with every line defining it in the loaded document,
+// and reports whether the key was present
+func (env *EnvContent) Delete(key string) bool {
+	_, found := env.keyValuePairs[key]
+
+	env.remove(key)
+	delete(env.sources, key)
+	env.deleteLines(key)
+
+	return found
+}
+
+// store sets the value of a key, a new key is added after the ones already defined.
+func (env *EnvContent) store(key string, value string) {
+	if _, found := env.keyValuePairs[key]; !found {
+		env.order = append(env.order, key)
+	}
 	env.keyValuePairs[key] = value
 }
+
+// remove deletes a key from the env map and from the order of the keys.
+func (env *EnvContent) remove(key string) {
+	if _, found := env.keyValuePairs[key]; !found {
+		return
+	}
+	delete(env.keyValuePairs, key)
+	env.order = slices.DeleteFunc(env.order, func(defined string) bool { return defined == key })
+}
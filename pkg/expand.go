@@ -0,0 +1,278 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Errors returned when the references in values can not be expanded.
+var (
+	ErrCyclicReference = errors.New("variables reference each other in a cycle")
+	ErrBadSubstitution = errors.New("bad variable substitution")
+)
+
+// RequiredVariableError is returned when a ${VAR:?message} or ${VAR?message} reference
+// finds its variable missing.
+type RequiredVariableError struct {
+	// File is empty when the content was loaded from a string.
+	File string
+	// Key holds the reference in its value.
+	Key string
+	// Line is the line where Key is defined.
+	Line     int
+	Variable string
+	Message  string
+}
+
+func (e *RequiredVariableError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = "required variable is not set"
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", location(e.File, e.Line), e.Key, e.Variable, message)
+}
+
+// ExpandError locates a value whose references can not be expanded,
+// it wraps ErrCyclicReference or ErrBadSubstitution.
+type ExpandError struct {
+	// File is empty when the content was loaded from a string.
+	File string
+	// Key holds the reference in its value.
+	Key string
+	// Line is the line where Key is defined.
+	Line int
+	Err  error
+}
+
+func (e *ExpandError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", location(e.File, e.Line), e.Key, e.Err)
+}
+
+func (e *ExpandError) Unwrap() error {
+	return e.Err
+}
+
+// location formats a line and the file holding it when there is one.
+func location(file string, line int) string {
+	if file == "" {
+		return fmt.Sprintf("line %d", line)
+	}
+	return fmt.Sprintf("%s: line %d", file, line)
+}
+
+// expander resolves ${VAR} and $VAR references in the values loaded by a single call.
+// A reference is looked up in the values of the same load first, whatever their position,
+// then in the already loaded values and finally in the process environment.
+// A value referencing its own key is expanded from the process environment,
+// so PATH=${PATH}:/opt/bin extends the existing PATH.
+type expander struct {
+	env *EnvContent
+	// fileName is only used to locate errors.
+	fileName  string
+	templates map[string]entry
+	expanded  map[string]string
+	resolving []string
+}
+
+func newExpander(env *EnvContent, fileName string, templates map[string]entry) *expander {
+	return &expander{
+		env:       env,
+		fileName:  fileName,
+		templates: templates,
+		expanded:  make(map[string]string),
+	}
+}
+
+// resolve returns the expanded value of a key holding a template.
+func (e *expander) resolve(key string) (string, error) {
+	if value, ok := e.expanded[key]; ok {
+		return value, nil
+	}
+
+	for i, resolving := range e.resolving {
+		if resolving == key {
+			cycle := append(append([]string{}, e.resolving[i:]...), key)
+			return "", e.locate(key, fmt.Errorf("%w: %s", ErrCyclicReference, strings.Join(cycle, " -> ")))
+		}
+	}
+
+	e.resolving = append(e.resolving, key)
+	value, err := e.expand(e.templates[key].value, key)
+	e.resolving = e.resolving[:len(e.resolving)-1]
+	if err != nil {
+		return "", err
+	}
+
+	e.expanded[key] = value
+	return value, nil
+}
+
+// locate wraps an error found while expanding the value of key in an ExpandError.
+func (e *expander) locate(key string, err error) error {
+	return &ExpandError{File: e.fileName, Key: key, Line: e.templates[key].line, Err: err}
+}
+
+// lookup finds the value of a referenced variable while expanding the value of current.
+func (e *expander) lookup(name string, current string) (string, bool, error) {
+	if name != current {
+		if _, ok := e.templates[name]; ok {
+			value, err := e.resolve(name)
+			return value, true, err
+		}
+		if value, ok := e.env.keyValuePairs[name]; ok {
+			return value, true, nil
+		}
+	}
+
+	value, ok := os.LookupEnv(name)
+	return value, ok, nil
+}
+
+// expand replaces the references in a template, "$$" stands for a literal dollar sign.
+func (e *expander) expand(template string, current string) (string, error) {
+	var value strings.Builder
+
+	for i := 0; i < len(template); i++ {
+		if template[i] != '$' || i+1 == len(template) {
+			value.WriteByte(template[i])
+			continue
+		}
+
+		next := template[i+1]
+		switch {
+		case next == '$':
+			value.WriteByte('$')
+			i++
+		case next == '{':
+			end := closingBrace(template, i+2)
+			if end == -1 {
+				return "", e.locate(current, fmt.Errorf("%w: %s", ErrBadSubstitution, template[i:]))
+			}
+
+			substituted, err := e.substitute(template[i+2:end], current)
+			if err != nil {
+				return "", err
+			}
+
+			value.WriteString(substituted)
+			i = end
+		case isNameStart(next):
+			end := i + 2
+			for end < len(template) && isNameChar(template[end]) {
+				end++
+			}
+
+			substituted, _, err := e.lookup(template[i+1:end], current)
+			if err != nil {
+				return "", err
+			}
+
+			value.WriteString(substituted)
+			i = end - 1
+		default:
+			value.WriteByte('$')
+		}
+	}
+
+	return value.String(), nil
+}
+
+// substitute expands the expression found between the braces of ${...},
+// which is a variable name optionally followed by one of the shell operators
+// -, :-, ?, :?, + and :+ and a word that is expanded only when it is used.
+func (e *expander) substitute(expression string, current string) (string, error) {
+	end := 0
+	for end < len(expression) && isNameChar(expression[end]) {
+		end++
+	}
+
+	name, operator := expression[:end], expression[end:]
+	if !isName(name) {
+		return "", e.locate(current, fmt.Errorf("%w: ${%s}", ErrBadSubstitution, expression))
+	}
+
+	value, found, err := e.lookup(name, current)
+	if err != nil || operator == "" {
+		return value, err
+	}
+
+	checkEmpty := operator[0] == ':'
+	if checkEmpty {
+		operator = operator[1:]
+	}
+	if operator == "" {
+		return "", e.locate(current, fmt.Errorf("%w: ${%s}", ErrBadSubstitution, expression))
+	}
+
+	set := found && !(checkEmpty && value == "")
+	word := operator[1:]
+
+	switch operator[0] {
+	case '-':
+		if set {
+			return value, nil
+		}
+		return e.expand(word, current)
+	case '+':
+		if !set {
+			return "", nil
+		}
+		return e.expand(word, current)
+	case '?':
+		if set {
+			return value, nil
+		}
+		message, err := e.expand(word, current)
+		if err != nil {
+			return "", err
+		}
+		return "", &RequiredVariableError{
+			File:     e.fileName,
+			Key:      current,
+			Line:     e.templates[current].line,
+			Variable: name,
+			Message:  message,
+		}
+	}
+
+	return "", e.locate(current, fmt.Errorf("%w: ${%s}", ErrBadSubstitution, expression))
+}
+
+// closingBrace returns the index of the brace closing the one opened before start, or -1.
+func closingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isName(s string) bool {
+	if len(s) == 0 || !isNameStart(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isNameChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
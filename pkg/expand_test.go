@@ -0,0 +1,269 @@
+package dotenv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ExpandTestCase struct {
+	desc             string
+	input            string
+	environment      map[string]string
+	disableExpansion bool
+	expectedError    error
+	expectedMap      map[string]string
+}
+
+func TestENV_Expand(t *testing.T) {
+	testCases := []ExpandTestCase{
+		{
+			desc: "Braced and plain references to previous keys",
+			input: "HOST=localhost\n" +
+				"PORT=8080\n" +
+				"BASE_URL=https://${HOST}:$PORT/api",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"HOST":     "localhost",
+				"PORT":     "8080",
+				"BASE_URL": "https://localhost:8080/api",
+			},
+		},
+		{
+			desc: "Forward reference",
+			input: "BASE_URL=\"http://${HOST}\"\n" +
+				"HOST=example.com",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"BASE_URL": "http://example.com",
+				"HOST":     "example.com",
+			},
+		},
+		{
+			desc: "Chained references",
+			input: "C=${B}c\n" +
+				"B=${A}b\n" +
+				"A=a",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"A": "a",
+				"B": "ab",
+				"C": "abc",
+			},
+		},
+		{
+			desc:          "Reference to the process environment",
+			input:         "DIR=${DOTENV_TEST_HOME}/app",
+			environment:   map[string]string{"DOTENV_TEST_HOME": "/home/user"},
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"DIR": "/home/user/app",
+			},
+		},
+		{
+			desc:          "Keys in the file win over the process environment",
+			input:         "DOTENV_TEST_HOME=/srv\nDIR=$DOTENV_TEST_HOME/app",
+			environment:   map[string]string{"DOTENV_TEST_HOME": "/home/user"},
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"DOTENV_TEST_HOME": "/srv",
+				"DIR":              "/srv/app",
+			},
+		},
+		{
+			desc:          "Self reference extends the process environment",
+			input:         "DOTENV_TEST_PATH=${DOTENV_TEST_PATH}:/opt/bin",
+			environment:   map[string]string{"DOTENV_TEST_PATH": "/usr/bin"},
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"DOTENV_TEST_PATH": "/usr/bin:/opt/bin",
+			},
+		},
+		{
+			desc:          "Undefined reference",
+			input:         "key=a${DOTENV_TEST_UNDEFINED}b",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"key": "ab",
+			},
+		},
+		{
+			desc:          "Single quoted value stays literal",
+			input:         "HOST=localhost\nkey='${HOST}'",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"HOST": "localhost",
+				"key":  "${HOST}",
+			},
+		},
+		{
+			desc:          "Escaped dollar signs",
+			input:         "HOST=localhost\nkey1=\"\\${HOST}\"\nkey2=$$HOST\nkey3=5$",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"HOST": "localhost",
+				"key1": "${HOST}",
+				"key2": "$HOST",
+				"key3": "5$",
+			},
+		},
+		{
+			desc:             "Expansion switched off",
+			input:            "HOST=localhost\nkey1=${HOST}\nkey2=\"\\$HOST\"",
+			disableExpansion: true,
+			expectedError:    nil,
+			expectedMap: map[string]string{
+				"HOST": "localhost",
+				"key1": "${HOST}",
+				"key2": "$HOST",
+			},
+		},
+		{
+			desc: "Default operators",
+			input: "EMPTY=\n" +
+				"SET=value\n" +
+				"key1=${DOTENV_TEST_UNDEFINED:-default}\n" +
+				"key2=${EMPTY:-default}\n" +
+				"key3=${EMPTY-default}\n" +
+				"key4=${DOTENV_TEST_UNDEFINED-default}\n" +
+				"key5=${SET:-default}",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"EMPTY": "",
+				"SET":   "value",
+				"key1":  "default",
+				"key2":  "default",
+				"key3":  "",
+				"key4":  "default",
+				"key5":  "value",
+			},
+		},
+		{
+			desc: "Alternative value operators",
+			input: "EMPTY=\n" +
+				"SET=value\n" +
+				"key1=${SET:+alternative}\n" +
+				"key2=${EMPTY:+alternative}\n" +
+				"key3=${EMPTY+alternative}\n" +
+				"key4=${DOTENV_TEST_UNDEFINED+alternative}",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"EMPTY": "",
+				"SET":   "value",
+				"key1":  "alternative",
+				"key2":  "",
+				"key3":  "alternative",
+				"key4":  "",
+			},
+		},
+		{
+			desc:          "Nested references in the default",
+			input:         "HOST=localhost\nURL=${DOTENV_TEST_URL:-http://${HOST}:${PORT:-80}}",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"HOST": "localhost",
+				"URL":  "http://localhost:80",
+			},
+		},
+		{
+			desc:          "Required variable that is set",
+			input:         "NAME=app\nkey=${NAME:?NAME must be set}",
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"NAME": "app",
+				"key":  "app",
+			},
+		},
+		{
+			desc:          "Unknown operator",
+			input:         "key=${NAME:=value}",
+			expectedError: ErrBadSubstitution,
+			expectedMap:   nil,
+		},
+		{
+			desc:          "Cyclic references",
+			input:         "A=${B}\nB=${C}\nC=$A",
+			expectedError: ErrCyclicReference,
+			expectedMap:   nil,
+		},
+		{
+			desc:          "Unclosed brace",
+			input:         "key=${HOST",
+			expectedError: ErrBadSubstitution,
+			expectedMap:   nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			for key, value := range test.environment {
+				t.Setenv(key, value)
+			}
+
+			parser := EnvContent{DisableExpansion: test.disableExpansion}
+			resultedMap, resultedError := parser.LoadFromString(test.input)
+
+			assert.ErrorIs(t, resultedError, test.expectedError)
+			if test.expectedMap != nil && !reflect.DeepEqual(test.expectedMap, resultedMap) {
+				t.Fail()
+			}
+
+		})
+	}
+}
+
+func TestENV_ExpandRequired(t *testing.T) {
+	parser := EnvContent{}
+	_, err := parser.LoadFromString("# database\n" +
+		"DB_HOST=localhost\n" +
+		"DB_URL=postgres://${DB_HOST}/${DB_NAME:?database name is required}\n")
+
+	var requiredError *RequiredVariableError
+	if !errors.As(err, &requiredError) {
+		t.Fatalf("expected a RequiredVariableError, got %v", err)
+	}
+
+	assert.Equal(t, "DB_URL", requiredError.Key)
+	assert.Equal(t, 3, requiredError.Line)
+	assert.Equal(t, "DB_NAME", requiredError.Variable)
+	assert.Equal(t, "database name is required", requiredError.Message)
+	assert.Equal(t, "line 3: DB_URL: DB_NAME: database name is required", err.Error())
+}
+
+func TestENV_ExpandRequiredFromFile(t *testing.T) {
+	parser := EnvContent{}
+	_, err := parser.LoadFromFile("testdata/test_26.txt")
+
+	var requiredError *RequiredVariableError
+	if !errors.As(err, &requiredError) {
+		t.Fatalf("expected a RequiredVariableError, got %v", err)
+	}
+
+	assert.Equal(t, "testdata/test_26.txt", requiredError.File)
+	assert.Equal(t, "testdata/test_26.txt: line 1: A: NOPE_ZZ: need it", err.Error())
+
+	_, err = parser.LoadFromFiles([]string{"testdata/test_26.txt"})
+	assert.Equal(t, "testdata/test_26.txt: line 1: A: NOPE_ZZ: need it", err.Error())
+}
+
+func TestENV_ExpandErrorLocation(t *testing.T) {
+	parser := EnvContent{}
+	_, err := parser.LoadFromString("# settings\nHOST=localhost\nA=${B\n")
+
+	var expandError *ExpandError
+	if !errors.As(err, &expandError) {
+		t.Fatalf("expected an ExpandError, got %v", err)
+	}
+
+	assert.ErrorIs(t, err, ErrBadSubstitution)
+	assert.Equal(t, "A", expandError.Key)
+	assert.Equal(t, 3, expandError.Line)
+	assert.Equal(t, "line 3: A: bad variable substitution: ${B", err.Error())
+
+	_, err = parser.LoadFromFile("testdata/test_25.txt")
+
+	assert.ErrorIs(t, err, ErrCyclicReference)
+	assert.Equal(t, "testdata/test_25.txt: line 1: B: variables reference each other in a cycle: B -> C -> B", err.Error())
+}
@@ -0,0 +1,336 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidValue is matched by the errors of the typed getters when a value can not be converted.
+var ErrInvalidValue = errors.New("invalid value")
+
+// ValueError is returned by the typed getters when the value of a key can not be converted.
+type ValueError struct {
+	Key   string
+	Value string
+	// Type is the name of the type the value was converted to.
+	Type string
+	Err  error
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("%s=%q is not a valid %s: %v", e.Key, e.Value, e.Type, e.Err)
+}
+
+// Unwrap allows matching a ValueError with ErrInvalidValue and with the conversion error.
+func (e *ValueError) Unwrap() []error {
+	return []error{ErrInvalidValue, e.Err}
+}
+
+// getParsed converts the value of key with parse, naming the key and the value in errors.
+func getParsed[T any](env *EnvContent, key string, typeName string, parse func(string) (T, error)) (T, error) {
+	value, err := env.Get(key)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("%w: %s", err, key)
+	}
+
+	return convert(key, value, typeName, parse)
+}
+
+// getParsedOr converts the value of key with parse, fallback is returned when the key is missing or empty.
+func getParsedOr[T any](env *EnvContent, key string, fallback T, typeName string, parse func(string) (T, error)) (T, error) {
+	value, found := env.Lookup(key)
+	if !found || value == "" {
+		return fallback, nil
+	}
+
+	return convert(key, value, typeName, parse)
+}
+
+func convert[T any](key string, value string, typeName string, parse func(string) (T, error)) (T, error) {
+	parsed, err := parse(strings.TrimSpace(value))
+	if err != nil {
+		var zero T
+		return zero, newValueError(key, value, typeName, err)
+	}
+
+	return parsed, nil
+}
+
+func newValueError(key string, value string, typeName string, err error) *ValueError {
+	return &ValueError{Key: key, Value: value, Type: typeName, Err: numErrorReason(err)}
+}
+
+// numErrorReason keeps only the reason of strconv errors, the value is already part of the messages.
+func numErrorReason(err error) error {
+	if numError, ok := err.(*strconv.NumError); ok {
+		return numError.Err
+	}
+	return err
+}
+
+func parseInt[T int | int8 | int16 | int32 | int64](bitSize int) func(string) (T, error) {
+	return func(value string) (T, error) {
+		parsed, err := strconv.ParseInt(value, 10, bitSize)
+		return T(parsed), err
+	}
+}
+
+func parseUint[T uint | uint8 | uint16 | uint32 | uint64](bitSize int) func(string) (T, error) {
+	return func(value string) (T, error) {
+		parsed, err := strconv.ParseUint(value, 10, bitSize)
+		return T(parsed), err
+	}
+}
+
+func parseFloat[T float32 | float64](bitSize int) func(string) (T, error) {
+	return func(value string) (T, error) {
+		parsed, err := strconv.ParseFloat(value, bitSize)
+		return T(parsed), err
+	}
+}
+
+// parseBool accepts true/false, 1/0, yes/no and on/off in any case.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "1", "yes", "on":
+		return true, nil
+	case "false", "0", "no", "off":
+		return false, nil
+	}
+	return false, errors.New("expected true/false, 1/0, yes/no or on/off")
+}
+
+// parseURL accepts absolute URLs only, so a missing scheme is reported.
+func parseURL(value string) (*url.URL, error) {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return nil, errors.Unwrap(err)
+	}
+	if parsed.Scheme == "" {
+		return nil, errors.New("missing scheme")
+	}
+	return parsed, nil
+}
+
+// byteUnits maps the lower case unit suffixes to their size,
+// SI units are powers of 1000 while IEC units and single letters are powers of 1024.
+var byteUnits = map[string]uint64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kb":  1e3,
+	"kib": 1 << 10,
+	"m":   1 << 20,
+	"mb":  1e6,
+	"mib": 1 << 20,
+	"g":   1 << 30,
+	"gb":  1e9,
+	"gib": 1 << 30,
+	"t":   1 << 40,
+	"tb":  1e12,
+	"tib": 1 << 40,
+	"p":   1 << 50,
+	"pb":  1e15,
+	"pib": 1 << 50,
+}
+
+// parseByteSize parses human readable sizes like 512MiB, 1.5GB or 64k into a number of bytes.
+func parseByteSize(value string) (uint64, error) {
+	end := 0
+	for end < len(value) && (value[end] == '.' || (value[end] >= '0' && value[end] <= '9')) {
+		end++
+	}
+
+	unit, found := byteUnits[strings.ToLower(strings.TrimSpace(value[end:]))]
+	if !found {
+		return 0, fmt.Errorf("unknown unit %q", strings.TrimSpace(value[end:]))
+	}
+
+	number := value[:end]
+	if !strings.Contains(number, ".") {
+		size, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if size > math.MaxUint64/unit {
+			return 0, strconv.ErrRange
+		}
+		return size * unit, nil
+	}
+
+	size, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+	size *= float64(unit)
+	if size >= math.MaxUint64 {
+		return 0, strconv.ErrRange
+	}
+	return uint64(size), nil
+}
+
+// GetInt retrieves the value of a key as an int
+func (env *EnvContent) GetInt(key string) (int, error) {
+	return getParsed(env, key, "int", parseInt[int](0))
+}
+
+// GetIntOr retrieves the value of a key as an int, or fallback when the key is missing or empty
+func (env *EnvContent) GetIntOr(key string, fallback int) (int, error) {
+	return getParsedOr(env, key, fallback, "int", parseInt[int](0))
+}
+
+// GetInt8 retrieves the value of a key as an int8
+func (env *EnvContent) GetInt8(key string) (int8, error) {
+	return getParsed(env, key, "int8", parseInt[int8](8))
+}
+
+// GetInt8Or retrieves the value of a key as an int8, or fallback when the key is missing or empty
+func (env *EnvContent) GetInt8Or(key string, fallback int8) (int8, error) {
+	return getParsedOr(env, key, fallback, "int8", parseInt[int8](8))
+}
+
+// GetInt16 retrieves the value of a key as an int16
+func (env *EnvContent) GetInt16(key string) (int16, error) {
+	return getParsed(env, key, "int16", parseInt[int16](16))
+}
+
+// GetInt16Or retrieves the value of a key as an int16, or fallback when the key is missing or empty
+func (env *EnvContent) GetInt16Or(key string, fallback int16) (int16, error) {
+	return getParsedOr(env, key, fallback, "int16", parseInt[int16](16))
+}
+
+// GetInt32 retrieves the value of a key as an int32
+func (env *EnvContent) GetInt32(key string) (int32, error) {
+	return getParsed(env, key, "int32", parseInt[int32](32))
+}
+
+// GetInt32Or retrieves the value of a key as an int32, or fallback when the key is missing or empty
+func (env *EnvContent) GetInt32Or(key string, fallback int32) (int32, error) {
+	return getParsedOr(env, key, fallback, "int32", parseInt[int32](32))
+}
+
+// GetInt64 retrieves the value of a key as an int64
+func (env *EnvContent) GetInt64(key string) (int64, error) {
+	return getParsed(env, key, "int64", parseInt[int64](64))
+}
+
+// GetInt64Or retrieves the value of a key as an int64, or fallback when the key is missing or empty
+func (env *EnvContent) GetInt64Or(key string, fallback int64) (int64, error) {
+	return getParsedOr(env, key, fallback, "int64", parseInt[int64](64))
+}
+
+// GetUint retrieves the value of a key as an uint
+func (env *EnvContent) GetUint(key string) (uint, error) {
+	return getParsed(env, key, "uint", parseUint[uint](0))
+}
+
+// GetUintOr retrieves the value of a key as an uint, or fallback when the key is missing or empty
+func (env *EnvContent) GetUintOr(key string, fallback uint) (uint, error) {
+	return getParsedOr(env, key, fallback, "uint", parseUint[uint](0))
+}
+
+// GetUint8 retrieves the value of a key as an uint8
+func (env *EnvContent) GetUint8(key string) (uint8, error) {
+	return getParsed(env, key, "uint8", parseUint[uint8](8))
+}
+
+// GetUint8Or retrieves the value of a key as an uint8, or fallback when the key is missing or empty
+func (env *EnvContent) GetUint8Or(key string, fallback uint8) (uint8, error) {
+	return getParsedOr(env, key, fallback, "uint8", parseUint[uint8](8))
+}
+
+// GetUint16 retrieves the value of a key as an uint16
+func (env *EnvContent) GetUint16(key string) (uint16, error) {
+	return getParsed(env, key, "uint16", parseUint[uint16](16))
+}
+
+// GetUint16Or retrieves the value of a key as an uint16, or fallback when the key is missing or empty
+func (env *EnvContent) GetUint16Or(key string, fallback uint16) (uint16, error) {
+	return getParsedOr(env, key, fallback, "uint16", parseUint[uint16](16))
+}
+
+// GetUint32 retrieves the value of a key as an uint32
+func (env *EnvContent) GetUint32(key string) (uint32, error) {
+	return getParsed(env, key, "uint32", parseUint[uint32](32))
+}
+
+// GetUint32Or retrieves the value of a key as an uint32, or fallback when the key is missing or empty
+func (env *EnvContent) GetUint32Or(key string, fallback uint32) (uint32, error) {
+	return getParsedOr(env, key, fallback, "uint32", parseUint[uint32](32))
+}
+
+// GetUint64 retrieves the value of a key as an uint64
+func (env *EnvContent) GetUint64(key string) (uint64, error) {
+	return getParsed(env, key, "uint64", parseUint[uint64](64))
+}
+
+// GetUint64Or retrieves the value of a key as an uint64, or fallback when the key is missing or empty
+func (env *EnvContent) GetUint64Or(key string, fallback uint64) (uint64, error) {
+	return getParsedOr(env, key, fallback, "uint64", parseUint[uint64](64))
+}
+
+// GetFloat32 retrieves the value of a key as a float32
+func (env *EnvContent) GetFloat32(key string) (float32, error) {
+	return getParsed(env, key, "float32", parseFloat[float32](32))
+}
+
+// GetFloat32Or retrieves the value of a key as a float32, or fallback when the key is missing or empty
+func (env *EnvContent) GetFloat32Or(key string, fallback float32) (float32, error) {
+	return getParsedOr(env, key, fallback, "float32", parseFloat[float32](32))
+}
+
+// GetFloat64 retrieves the value of a key as a float64
+func (env *EnvContent) GetFloat64(key string) (float64, error) {
+	return getParsed(env, key, "float64", parseFloat[float64](64))
+}
+
+// GetFloat64Or retrieves the value of a key as a float64, or fallback when the key is missing or empty
+func (env *EnvContent) GetFloat64Or(key string, fallback float64) (float64, error) {
+	return getParsedOr(env, key, fallback, "float64", parseFloat[float64](64))
+}
+
+// GetBool retrieves the value of a key as a bool, accepting true/false, 1/0, yes/no and on/off
+func (env *EnvContent) GetBool(key string) (bool, error) {
+	return getParsed(env, key, "bool", parseBool)
+}
+
+// GetBoolOr retrieves the value of a key as a bool, or fallback when the key is missing or empty
+func (env *EnvContent) GetBoolOr(key string, fallback bool) (bool, error) {
+	return getParsedOr(env, key, fallback, "bool", parseBool)
+}
+
+// GetDuration retrieves the value of a key as a time.Duration like 1h30m
+func (env *EnvContent) GetDuration(key string) (time.Duration, error) {
+	return getParsed(env, key, "duration", time.ParseDuration)
+}
+
+// GetDurationOr retrieves the value of a key as a time.Duration, or fallback when the key is missing or empty
+func (env *EnvContent) GetDurationOr(key string, fallback time.Duration) (time.Duration, error) {
+	return getParsedOr(env, key, fallback, "duration", time.ParseDuration)
+}
+
+// GetURL retrieves the value of a key as an absolute URL
+func (env *EnvContent) GetURL(key string) (*url.URL, error) {
+	return getParsed(env, key, "URL", parseURL)
+}
+
+// GetURLOr retrieves the value of a key as an absolute URL, or fallback when the key is missing or empty
+func (env *EnvContent) GetURLOr(key string, fallback *url.URL) (*url.URL, error) {
+	return getParsedOr(env, key, fallback, "URL", parseURL)
+}
+
+// GetByteSize retrieves the value of a key like 512MiB or 1.5GB as a number of bytes
+func (env *EnvContent) GetByteSize(key string) (uint64, error) {
+	return getParsed(env, key, "byte size", parseByteSize)
+}
+
+// GetByteSizeOr retrieves the value of a key as a number of bytes, or fallback when the key is missing or empty
+func (env *EnvContent) GetByteSizeOr(key string, fallback uint64) (uint64, error) {
+	return getParsedOr(env, key, fallback, "byte size", parseByteSize)
+}
@@ -0,0 +1,241 @@
+package dotenv
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TypedGetterTestCase struct {
+	desc          string
+	get           func(env *EnvContent) (any, error)
+	expectedValue any
+	expectedError error
+}
+
+const typedGettersInput = "PORT=8080\n" +
+	"OFFSET=-12\n" +
+	"SMALL=300\n" +
+	"RATIO=0.75\n" +
+	"DEBUG=yes\n" +
+	"VERBOSE=Off\n" +
+	"TIMEOUT=1m30s\n" +
+	"ENDPOINT=https://api.example.com:8443/v1?debug=true\n" +
+	"RELATIVE=/v1/users\n" +
+	"CACHE=512MiB\n" +
+	"UPLOAD=1.5GB\n" +
+	"BUFFER=64k\n" +
+	"EMPTY=\n" +
+	"WORD=abc\n" +
+	"ALLOWED_ORIGINS=a.com, b.com ,c.com,\n" +
+	"PATHS=/usr/bin;/opt/bin\n" +
+	"NAMES=`\"Doe, John\",'Roe, Jane',\"\"`\n" +
+	"BROKEN=a,\"b,c\n" +
+	"PORTS=80, 443,8080\n" +
+	"BAD_PORTS=80,http,443\n" +
+	"TIMEOUTS=1s,250ms,1m\n" +
+	"FLAGS=yes|off|1\n" +
+	"LABELS=team:core, tier:1,owner:\"a:b, c\"\n" +
+	"QUOTED_LABELS=\"'x:y':z, 'a,b':'c:d'\"\n" +
+	"QUOTED_ENTRY=\"'x:y'\"\n" +
+	"BAD_LABELS=team:core,tier\n" +
+	"EMPTY_KEY=:value"
+
+func TestENV_TypedGetters(t *testing.T) {
+	parser := EnvContent{}
+	_, _ = parser.LoadFromString(typedGettersInput)
+
+	endpoint, _ := url.Parse("https://api.example.com:8443/v1?debug=true")
+	fallbackURL, _ := url.Parse("http://localhost")
+
+	testCases := []TypedGetterTestCase{
+		{
+			desc:          "Int",
+			get:           func(env *EnvContent) (any, error) { return env.GetInt("PORT") },
+			expectedValue: 8080,
+		},
+		{
+			desc:          "Negative int64",
+			get:           func(env *EnvContent) (any, error) { return env.GetInt64("OFFSET") },
+			expectedValue: int64(-12),
+		},
+		{
+			desc:          "Int16",
+			get:           func(env *EnvContent) (any, error) { return env.GetInt16("SMALL") },
+			expectedValue: int16(300),
+		},
+		{
+			desc:          "Int8 out of range",
+			get:           func(env *EnvContent) (any, error) { return env.GetInt8("SMALL") },
+			expectedValue: int8(0),
+			expectedError: strconv.ErrRange,
+		},
+		{
+			desc:          "Uint32",
+			get:           func(env *EnvContent) (any, error) { return env.GetUint32("PORT") },
+			expectedValue: uint32(8080),
+		},
+		{
+			desc:          "Negative uint",
+			get:           func(env *EnvContent) (any, error) { return env.GetUint("OFFSET") },
+			expectedValue: uint(0),
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Float64",
+			get:           func(env *EnvContent) (any, error) { return env.GetFloat64("RATIO") },
+			expectedValue: 0.75,
+		},
+		{
+			desc:          "Invalid int",
+			get:           func(env *EnvContent) (any, error) { return env.GetInt("WORD") },
+			expectedValue: 0,
+			expectedError: strconv.ErrSyntax,
+		},
+		{
+			desc:          "Missing int",
+			get:           func(env *EnvContent) (any, error) { return env.GetInt("MISSING") },
+			expectedValue: 0,
+			expectedError: ErrMissingValue,
+		},
+		{
+			desc:          "Empty int",
+			get:           func(env *EnvContent) (any, error) { return env.GetInt("EMPTY") },
+			expectedValue: 0,
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Bool yes",
+			get:           func(env *EnvContent) (any, error) { return env.GetBool("DEBUG") },
+			expectedValue: true,
+		},
+		{
+			desc:          "Bool off in mixed case",
+			get:           func(env *EnvContent) (any, error) { return env.GetBool("VERBOSE") },
+			expectedValue: false,
+		},
+		{
+			desc:          "Invalid bool",
+			get:           func(env *EnvContent) (any, error) { return env.GetBool("WORD") },
+			expectedValue: false,
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Duration",
+			get:           func(env *EnvContent) (any, error) { return env.GetDuration("TIMEOUT") },
+			expectedValue: 90 * time.Second,
+		},
+		{
+			desc:          "Invalid duration",
+			get:           func(env *EnvContent) (any, error) { return env.GetDuration("PORT") },
+			expectedValue: time.Duration(0),
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "URL",
+			get:           func(env *EnvContent) (any, error) { return env.GetURL("ENDPOINT") },
+			expectedValue: endpoint,
+		},
+		{
+			desc:          "Relative URL",
+			get:           func(env *EnvContent) (any, error) { return env.GetURL("RELATIVE") },
+			expectedValue: (*url.URL)(nil),
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "IEC byte size",
+			get:           func(env *EnvContent) (any, error) { return env.GetByteSize("CACHE") },
+			expectedValue: uint64(512 << 20),
+		},
+		{
+			desc:          "Fractional SI byte size",
+			get:           func(env *EnvContent) (any, error) { return env.GetByteSize("UPLOAD") },
+			expectedValue: uint64(1500000000),
+		},
+		{
+			desc:          "Single letter byte size",
+			get:           func(env *EnvContent) (any, error) { return env.GetByteSize("BUFFER") },
+			expectedValue: uint64(64 << 10),
+		},
+		{
+			desc:          "Byte size without unit",
+			get:           func(env *EnvContent) (any, error) { return env.GetByteSize("PORT") },
+			expectedValue: uint64(8080),
+		},
+		{
+			desc:          "Byte size with unknown unit",
+			get:           func(env *EnvContent) (any, error) { return env.GetByteSize("TIMEOUT") },
+			expectedValue: uint64(0),
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Int or fallback for missing key",
+			get:           func(env *EnvContent) (any, error) { return env.GetIntOr("MISSING", 3000) },
+			expectedValue: 3000,
+		},
+		{
+			desc:          "Int or fallback for empty value",
+			get:           func(env *EnvContent) (any, error) { return env.GetIntOr("EMPTY", 3000) },
+			expectedValue: 3000,
+		},
+		{
+			desc:          "Int or fallback for present key",
+			get:           func(env *EnvContent) (any, error) { return env.GetIntOr("PORT", 3000) },
+			expectedValue: 8080,
+		},
+		{
+			desc:          "Int or fallback for invalid value",
+			get:           func(env *EnvContent) (any, error) { return env.GetIntOr("WORD", 3000) },
+			expectedValue: 0,
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Bool or fallback",
+			get:           func(env *EnvContent) (any, error) { return env.GetBoolOr("MISSING", true) },
+			expectedValue: true,
+		},
+		{
+			desc:          "Duration or fallback",
+			get:           func(env *EnvContent) (any, error) { return env.GetDurationOr("MISSING", time.Second) },
+			expectedValue: time.Second,
+		},
+		{
+			desc:          "URL or fallback",
+			get:           func(env *EnvContent) (any, error) { return env.GetURLOr("MISSING", fallbackURL) },
+			expectedValue: fallbackURL,
+		},
+		{
+			desc:          "Byte size or fallback",
+			get:           func(env *EnvContent) (any, error) { return env.GetByteSizeOr("MISSING", 1024) },
+			expectedValue: uint64(1024),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			resultedValue, resultedError := test.get(&parser)
+
+			assert.ErrorIs(t, resultedError, test.expectedError)
+			assert.Equal(t, test.expectedValue, resultedValue)
+		})
+	}
+}
+
+func TestENV_ValueError(t *testing.T) {
+	parser := EnvContent{}
+	_, _ = parser.LoadFromString("PORT=80a")
+
+	_, err := parser.GetInt("PORT")
+
+	var valueError *ValueError
+	if !errors.As(err, &valueError) {
+		t.Fatalf("expected a ValueError, got %v", err)
+	}
+	assert.Equal(t, "PORT", valueError.Key)
+	assert.Equal(t, "80a", valueError.Value)
+	assert.Equal(t, `PORT="80a" is not a valid int: invalid syntax`, err.Error())
+}
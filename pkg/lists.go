@@ -0,0 +1,181 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// splitList splits a value on separator outside of single and double quotes,
+// trims the items and strips their quotes. Empty items that are not quoted are dropped
+// so trailing separators are allowed.
+func splitList(value string, separator string) ([]string, error) {
+	items, err := splitQuoted(value, separator)
+	for i, item := range items {
+		items[i] = unquote(item)
+	}
+	return items, err
+}
+
+// splitQuoted splits a value on separator outside of single and double quotes
+// and trims the items, keeping their quotes.
+func splitQuoted(value string, separator string) ([]string, error) {
+	if separator == "" {
+		return nil, errors.New("empty separator")
+	}
+
+	items := []string{}
+	for {
+		end, quote := indexUnquoted(value, separator)
+		if end == -1 {
+			if quote != 0 {
+				return nil, fmt.Errorf("unterminated %c quote", quote)
+			}
+			return appendItem(items, value), nil
+		}
+
+		items = appendItem(items, value[:end])
+		value = value[end+len(separator):]
+	}
+}
+
+// indexUnquoted returns the index of the first separator outside of single and double quotes, or -1
+// along with the quote left open at the end of value.
+func indexUnquoted(value string, separator string) (int, byte) {
+	var quote byte
+
+	for i := 0; i < len(value); i++ {
+		switch {
+		case quote != 0:
+			if value[i] == quote {
+				quote = 0
+			}
+		case value[i] == '"' || value[i] == '\'':
+			quote = value[i]
+		case strings.HasPrefix(value[i:], separator):
+			return i, 0
+		}
+	}
+
+	return -1, quote
+}
+
+func appendItem(items []string, item string) []string {
+	item = strings.TrimSpace(item)
+	if item == "" {
+		return items
+	}
+	return append(items, item)
+}
+
+// unquote strips the single or double quotes surrounding s.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// getList splits the value of key and converts every item with parse.
+func getList[T any](env *EnvContent, key string, separator string, typeName string, parse func(string) (T, error)) ([]T, error) {
+	items, err := env.GetList(key, separator)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make([]T, 0, len(items))
+	for i, item := range items {
+		value, err := convert(fmt.Sprintf("%s[%d]", key, i), item, typeName, parse)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, value)
+	}
+
+	return parsed, nil
+}
+
+// GetList retrieves the value of a key split on separator, like a.com,b.com.
+// Items are trimmed and may be quoted to hold the separator.
+func (env *EnvContent) GetList(key string, separator string) ([]string, error) {
+	value, err := env.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, key)
+	}
+
+	items, err := splitList(value, separator)
+	if err != nil {
+		return nil, &ValueError{Key: key, Value: value, Type: "list", Err: err}
+	}
+
+	return items, nil
+}
+
+// GetIntList retrieves the value of a key split on separator as ints
+func (env *EnvContent) GetIntList(key string, separator string) ([]int, error) {
+	return getList(env, key, separator, "int", parseInt[int](0))
+}
+
+// GetInt64List retrieves the value of a key split on separator as int64s
+func (env *EnvContent) GetInt64List(key string, separator string) ([]int64, error) {
+	return getList(env, key, separator, "int64", parseInt[int64](64))
+}
+
+// GetFloat64List retrieves the value of a key split on separator as float64s
+func (env *EnvContent) GetFloat64List(key string, separator string) ([]float64, error) {
+	return getList(env, key, separator, "float64", parseFloat[float64](64))
+}
+
+// GetBoolList retrieves the value of a key split on separator as bools
+func (env *EnvContent) GetBoolList(key string, separator string) ([]bool, error) {
+	return getList(env, key, separator, "bool", parseBool)
+}
+
+// GetDurationList retrieves the value of a key split on separator as time.Durations
+func (env *EnvContent) GetDurationList(key string, separator string) ([]time.Duration, error) {
+	return getList(env, key, separator, "duration", time.ParseDuration)
+}
+
+// GetMap retrieves the value of a key like team:core,tier:1 as a map,
+// items are split on separator and their key and value on keyValueSeparator.
+// Keys and values may be quoted to hold the separators.
+func (env *EnvContent) GetMap(key string, separator string, keyValueSeparator string) (map[string]string, error) {
+	value, err := env.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, key)
+	}
+
+	items, err := splitQuoted(value, separator)
+	if err != nil {
+		return nil, &ValueError{Key: key, Value: value, Type: "map", Err: err}
+	}
+
+	pairs := make(map[string]string, len(items))
+	for i, item := range items {
+		end := -1
+		if keyValueSeparator != "" {
+			end, _ = indexUnquoted(item, keyValueSeparator)
+		}
+
+		var itemKey, itemValue string
+		if end != -1 {
+			itemKey = unquote(strings.TrimSpace(item[:end]))
+			itemValue = item[end+len(keyValueSeparator):]
+		}
+
+		switch {
+		case end == -1:
+			err = fmt.Errorf("missing %q separator", keyValueSeparator)
+		case itemKey == "":
+			err = errors.New("missing key")
+		}
+		if err != nil {
+			return nil, &ValueError{Key: fmt.Sprintf("%s[%d]", key, i), Value: item, Type: "map entry", Err: err}
+		}
+
+		pairs[itemKey] = unquote(strings.TrimSpace(itemValue))
+	}
+
+	return pairs, nil
+}
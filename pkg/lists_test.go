@@ -0,0 +1,128 @@
+package dotenv
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestENV_ListGetters(t *testing.T) {
+	parser := EnvContent{}
+	_, _ = parser.LoadFromString(typedGettersInput)
+
+	testCases := []TypedGetterTestCase{
+		{
+			desc:          "Comma separated list",
+			get:           func(env *EnvContent) (any, error) { return env.GetList("ALLOWED_ORIGINS", ",") },
+			expectedValue: []string{"a.com", "b.com", "c.com"},
+		},
+		{
+			desc:          "Custom separator",
+			get:           func(env *EnvContent) (any, error) { return env.GetList("PATHS", ";") },
+			expectedValue: []string{"/usr/bin", "/opt/bin"},
+		},
+		{
+			desc:          "Quoted items",
+			get:           func(env *EnvContent) (any, error) { return env.GetList("NAMES", ",") },
+			expectedValue: []string{"Doe, John", "Roe, Jane", ""},
+		},
+		{
+			desc:          "Empty list",
+			get:           func(env *EnvContent) (any, error) { return env.GetList("EMPTY", ",") },
+			expectedValue: []string{},
+		},
+		{
+			desc:          "Unterminated quote",
+			get:           func(env *EnvContent) (any, error) { return env.GetList("BROKEN", ",") },
+			expectedValue: []string(nil),
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Missing list",
+			get:           func(env *EnvContent) (any, error) { return env.GetList("MISSING", ",") },
+			expectedValue: []string(nil),
+			expectedError: ErrMissingValue,
+		},
+		{
+			desc:          "Int list",
+			get:           func(env *EnvContent) (any, error) { return env.GetIntList("PORTS", ",") },
+			expectedValue: []int{80, 443, 8080},
+		},
+		{
+			desc:          "Invalid int list",
+			get:           func(env *EnvContent) (any, error) { return env.GetIntList("BAD_PORTS", ",") },
+			expectedValue: []int(nil),
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Duration list",
+			get:           func(env *EnvContent) (any, error) { return env.GetDurationList("TIMEOUTS", ",") },
+			expectedValue: []time.Duration{time.Second, 250 * time.Millisecond, time.Minute},
+		},
+		{
+			desc:          "Bool list",
+			get:           func(env *EnvContent) (any, error) { return env.GetBoolList("FLAGS", "|") },
+			expectedValue: []bool{true, false, true},
+		},
+		{
+			desc: "Map",
+			get:  func(env *EnvContent) (any, error) { return env.GetMap("LABELS", ",", ":") },
+			expectedValue: map[string]string{
+				"team":  "core",
+				"tier":  "1",
+				"owner": "a:b, c",
+			},
+		},
+		{
+			desc:          "Map entry without separator",
+			get:           func(env *EnvContent) (any, error) { return env.GetMap("BAD_LABELS", ",", ":") },
+			expectedValue: map[string]string(nil),
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Map with quoted keys",
+			get:           func(env *EnvContent) (any, error) { return env.GetMap("QUOTED_LABELS", ",", ":") },
+			expectedValue: map[string]string{"x:y": "z", "a,b": "c:d"},
+		},
+		{
+			desc:          "Map entry quoted as a whole",
+			get:           func(env *EnvContent) (any, error) { return env.GetMap("QUOTED_ENTRY", ",", ":") },
+			expectedValue: map[string]string(nil),
+			expectedError: ErrInvalidValue,
+		},
+		{
+			desc:          "Map entry without key",
+			get:           func(env *EnvContent) (any, error) { return env.GetMap("EMPTY_KEY", ",", ":") },
+			expectedValue: map[string]string(nil),
+			expectedError: ErrInvalidValue,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			resultedValue, resultedError := test.get(&parser)
+
+			assert.ErrorIs(t, resultedError, test.expectedError)
+			assert.Equal(t, test.expectedValue, resultedValue)
+		})
+	}
+}
+
+func TestENV_ListValueError(t *testing.T) {
+	parser := EnvContent{}
+	_, _ = parser.LoadFromString(typedGettersInput)
+
+	_, err := parser.GetIntList("BAD_PORTS", ",")
+
+	var valueError *ValueError
+	if !errors.As(err, &valueError) {
+		t.Fatalf("expected a ValueError, got %v", err)
+	}
+	assert.Equal(t, "BAD_PORTS[1]", valueError.Key)
+	assert.Equal(t, "http", valueError.Value)
+
+	_, err = parser.GetMap("BAD_LABELS", ",", ":")
+	assert.Equal(t, `BAD_LABELS[1]="tier" is not a valid map entry: missing ":" separator`, err.Error())
+}
@@ -0,0 +1,206 @@
+package dotenv
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSource is returned by Marshal when it is not given a struct or a non-nil pointer to a struct.
+var ErrInvalidSource = errors.New("source must be a struct or a non-nil pointer to a struct")
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// Marshal writes the fields of a struct tagged for Bind as a .env document,
+// preceded by a comment holding their desc tag when there is one:
+//
+//	Port int `env:"PORT" desc:"port the server listens on"`
+//
+// Values are quoted and escaped when needed so loading the document gives them back.
+// Nil pointers and slices are written as empty values. Binding an empty EnvContent
+// before marshaling fills the defaults, which gives an example file.
+func Marshal(source any) ([]byte, error) {
+	value := reflect.ValueOf(source)
+	if value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, ErrInvalidSource
+	}
+	if !value.CanAddr() {
+		// a copy makes the fields addressable, so MarshalText methods on pointers are found
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+
+	var document bytes.Buffer
+	if err := marshalStruct(&document, value, ""); err != nil {
+		return nil, err
+	}
+
+	return document.Bytes(), nil
+}
+
+func marshalStruct(document *bytes.Buffer, value reflect.Value, prefix string) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key, tagged := field.Tag.Lookup("env")
+		if key == "-" {
+			continue
+		}
+
+		if !tagged {
+			if isNestedStruct(field.Type) {
+				nested := value.Field(i)
+				if nested.Kind() == reflect.Pointer {
+					if nested.IsNil() {
+						nested = reflect.New(field.Type.Elem())
+					}
+					nested = nested.Elem()
+				}
+				if err := marshalStruct(document, nested, prefix+field.Tag.Get("prefix")); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		separator := field.Tag.Get("separator")
+		if separator == "" {
+			separator = ","
+		}
+
+		formatted, err := formatField(value.Field(i), separator)
+		if err != nil {
+			return fmt.Errorf("%s: %w", prefix+key, err)
+		}
+
+		if desc := field.Tag.Get("desc"); desc != "" {
+			for _, line := range strings.Split(desc, "\n") {
+				document.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+			}
+		}
+		document.WriteString(prefix + key + "=" + quoteValue(formatted) + "\n")
+	}
+
+	return nil
+}
+
+// formatField converts a field to the text setField parses back.
+func formatField(value reflect.Value, separator string) (string, error) {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "", nil
+		}
+		if value.Type().Elem() == urlType {
+			return value.Interface().(*url.URL).String(), nil
+		}
+		return formatField(value.Elem(), separator)
+	}
+
+	if value.CanAddr() && value.Addr().Type().Implements(textMarshalerType) {
+		value = value.Addr()
+	}
+	if value.Type().Implements(textMarshalerType) {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	if value.Type() == durationType {
+		return time.Duration(value.Int()).String(), nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits()), nil
+	case reflect.Slice:
+		items := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			item, err := formatField(value.Index(i), separator)
+			if err != nil {
+				return "", fmt.Errorf("item %d: %w", i, err)
+			}
+			quoted, err := quoteItem(item, separator)
+			if err != nil {
+				return "", fmt.Errorf("item %d: %w", i, err)
+			}
+			items = append(items, quoted)
+		}
+		return strings.Join(items, separator), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", value.Type())
+}
+
+// quoteItem quotes a list item that splitList would not give back as is,
+// it fails for items holding both kinds of quotes since list items have no escape sequences.
+func quoteItem(item string, separator string) (string, error) {
+	if item != "" && item == strings.TrimSpace(item) && !strings.Contains(item, separator) &&
+		!strings.ContainsAny(item, `"'`) {
+		return item, nil
+	}
+	if !strings.Contains(item, `"`) {
+		return `"` + item + `"`, nil
+	}
+	if !strings.Contains(item, "'") {
+		return "'" + item + "'", nil
+	}
+	return "", fmt.Errorf("%q holds both single and double quotes", item)
+}
+
+// quoteValue returns value as it has to be written in a .env file to be loaded back unchanged,
+// with or without expansion: unquoted when it is safe, single quoted when possible,
+// and double quoted with escape sequences otherwise.
+func quoteValue(value string) string {
+	if isSafeUnquoted(value) {
+		return value
+	}
+
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\', '"', '$':
+			quoted.WriteByte('\\')
+			quoted.WriteByte(value[i])
+		case '\n':
+			quoted.WriteString(`\n`)
+		case '\r':
+			quoted.WriteString(`\r`)
+		default:
+			quoted.WriteByte(value[i])
+		}
+	}
+	quoted.WriteByte('"')
+
+	return quoted.String()
+}
+
+// isSafeUnquoted reports whether a value is loaded back unchanged without quotes,
+// by this package as well as by shells sourcing the file.
+func isSafeUnquoted(value string) bool {
+	return !strings.ContainsAny(value, " \t\n\r\"'`$\\#")
+}
@@ -0,0 +1,209 @@
+package dotenv
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type marshalConfig struct {
+	Name     string        `env:"NAME" desc:"name of the service"`
+	Greeting string        `env:"GREETING" desc:"first line\nsecond line"`
+	Port     uint16        `env:"PORT" default:"8080"`
+	Debug    bool          `env:"DEBUG"`
+	Ratio    float32       `env:"RATIO"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"5s"`
+	Endpoint *url.URL      `env:"ENDPOINT"`
+	Hosts    []string      `env:"HOSTS"`
+	Ports    []int         `env:"PORTS" separator:";"`
+	IP       net.IP        `env:"IP"`
+	Retries  *int          `env:"RETRIES"`
+	Ignored  string        `env:"-"`
+	Untagged string
+	Database databaseConfig `prefix:"DB_"`
+	Cache    *cacheConfig   `prefix:"CACHE_"`
+}
+
+type QuoteValueTestCase struct {
+	desc          string
+	value         string
+	expectedQuote string
+}
+
+func TestENV_Marshal(t *testing.T) {
+	retries := 3
+	endpoint, _ := url.Parse("https://api.example.com/v1?debug=true")
+	config := marshalConfig{
+		Name:     "api",
+		Greeting: "hello world",
+		Port:     9090,
+		Debug:    true,
+		Ratio:    0.25,
+		Timeout:  90 * time.Second,
+		Endpoint: endpoint,
+		Hosts:    []string{"a.com", "b, c.com"},
+		Ports:    []int{80, 443},
+		IP:       net.ParseIP("10.0.0.1"),
+		Retries:  &retries,
+		Ignored:  "ignored",
+		Untagged: "untagged",
+		Database: databaseConfig{Host: "db.internal", Port: 5432},
+	}
+
+	document, err := Marshal(&config)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "# name of the service\n"+
+		"NAME=api\n"+
+		"# first line\n"+
+		"# second line\n"+
+		"GREETING='hello world'\n"+
+		"PORT=9090\n"+
+		"DEBUG=true\n"+
+		"RATIO=0.25\n"+
+		"TIMEOUT=1m30s\n"+
+		"ENDPOINT=https://api.example.com/v1?debug=true\n"+
+		"HOSTS='a.com,\"b, c.com\"'\n"+
+		"PORTS=80;443\n"+
+		"IP=10.0.0.1\n"+
+		"RETRIES=3\n"+
+		"DB_HOST=db.internal\n"+
+		"DB_PORT=5432\n"+
+		"CACHE_SIZE=0\n", string(document))
+
+	parser := EnvContent{}
+	_, err = parser.LoadFromString(string(document))
+	assert.Equal(t, nil, err)
+
+	var loaded marshalConfig
+	assert.Equal(t, nil, parser.Bind(&loaded))
+
+	config.Ignored, config.Untagged, config.Cache = "", "", &cacheConfig{}
+	assert.Equal(t, config, loaded)
+}
+
+func TestENV_MarshalExample(t *testing.T) {
+	parser := EnvContent{}
+	var example marshalConfig
+	_ = parser.Bind(&example)
+
+	document, err := Marshal(example)
+
+	assert.Equal(t, nil, err)
+	assert.Contains(t, string(document), "PORT=8080\n")
+	assert.Contains(t, string(document), "TIMEOUT=5s\n")
+	assert.Contains(t, string(document), "ENDPOINT=\n")
+	assert.Contains(t, string(document), "DB_HOST=localhost\n")
+}
+
+func TestENV_MarshalNilPointers(t *testing.T) {
+	config := marshalConfig{Name: "api", Hosts: []string{}, Ports: []int{}, Cache: &cacheConfig{}}
+
+	document, err := Marshal(config)
+	assert.Equal(t, nil, err)
+	assert.Contains(t, string(document), "ENDPOINT=\n")
+	assert.Contains(t, string(document), "RETRIES=\n")
+
+	parser := EnvContent{}
+	_, err = parser.LoadFromString(string(document))
+	assert.Equal(t, nil, err)
+
+	var loaded marshalConfig
+	assert.Equal(t, nil, parser.Bind(&loaded))
+	assert.Equal(t, config, loaded)
+}
+
+type listConfig struct {
+	Items []string `env:"ITEMS"`
+}
+
+func TestENV_MarshalListItems(t *testing.T) {
+	config := listConfig{Items: []string{"it's", `say "hi"`, "a,b", " padded ", ""}}
+
+	document, err := Marshal(config)
+	assert.Equal(t, nil, err)
+
+	parser := EnvContent{}
+	_, err = parser.LoadFromString(string(document))
+	assert.Equal(t, nil, err)
+
+	var loaded listConfig
+	assert.Equal(t, nil, parser.Bind(&loaded))
+	assert.Equal(t, config, loaded)
+
+	_, err = Marshal(listConfig{Items: []string{`it's "x"`, "a b"}})
+	assert.ErrorContains(t, err, "ITEMS: item 0:")
+}
+
+type levelConfig struct {
+	Level logLevel `env:"LEVEL"`
+}
+
+func (l *logLevel) MarshalText() ([]byte, error) {
+	if *l == 0 {
+		return []byte("debug"), nil
+	}
+	return []byte("info"), nil
+}
+
+func TestENV_MarshalByValue(t *testing.T) {
+	config := levelConfig{Level: 1}
+
+	byValue, err := Marshal(config)
+	assert.Equal(t, nil, err)
+	byPointer, err := Marshal(&config)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, "LEVEL=info\n", string(byValue))
+	assert.Equal(t, string(byPointer), string(byValue))
+
+	parser := EnvContent{}
+	_, _ = parser.LoadFromString(string(byValue))
+	var loaded levelConfig
+	assert.Equal(t, nil, parser.Bind(&loaded))
+	assert.Equal(t, config, loaded)
+}
+
+func TestENV_MarshalInvalidSource(t *testing.T) {
+	_, err := Marshal((*marshalConfig)(nil))
+	assert.ErrorIs(t, err, ErrInvalidSource)
+
+	_, err = Marshal("NAME=api")
+	assert.ErrorIs(t, err, ErrInvalidSource)
+}
+
+func TestENV_QuoteValue(t *testing.T) {
+	testCases := []QuoteValueTestCase{
+		{desc: "Plain value", value: "value", expectedQuote: "value"},
+		{desc: "Empty value", value: "", expectedQuote: ""},
+		{desc: "Separators", value: "postgres://u:p@h/db?sslmode=disable", expectedQuote: "postgres://u:p@h/db?sslmode=disable"},
+		{desc: "Hash", value: "#fff", expectedQuote: "'#fff'"},
+		{desc: "Backslash", value: `C:\dir`, expectedQuote: `'C:\dir'`},
+		{desc: "Spaces", value: " hello world ", expectedQuote: "' hello world '"},
+		{desc: "Comment", value: "value # not a comment", expectedQuote: "'value # not a comment'"},
+		{desc: "Dollar sign", value: "pa$word", expectedQuote: "'pa$word'"},
+		{desc: "Leading quote", value: `"quoted"`, expectedQuote: `'"quoted"'`},
+		{desc: "Single quote", value: "it's $HOME", expectedQuote: `"it's \$HOME"`},
+		{desc: "New lines", value: "line 1\nline \"2\"\\", expectedQuote: `"line 1\nline \"2\"\\"`},
+		{desc: "Carriage return", value: "a\r\nb", expectedQuote: `"a\r\nb"`},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			quoted := quoteValue(test.value)
+			assert.Equal(t, test.expectedQuote, quoted)
+
+			for _, disableExpansion := range []bool{false, true} {
+				parser := EnvContent{DisableExpansion: disableExpansion}
+				_, _ = parser.LoadFromString("key=" + quoted)
+				value, err := parser.Get("key")
+
+				assert.Equal(t, nil, err)
+				assert.Equal(t, test.value, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseError describes a line of a .env file that is not in correct format.
+type ParseError struct {
+	// File is empty when the content was loaded from a string.
+	File   string
+	Line   int
+	Column int
+	// Text is the offending line as it appears in the content.
+	Text   string
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	location := fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+	if e.File != "" {
+		location = e.File + ": " + location
+	}
+	return fmt.Sprintf("%s: %s: %q", location, e.Reason, e.Text)
+}
+
+// Unwrap allows matching any ParseError with ErrWrongFormat.
+func (e *ParseError) Unwrap() error {
+	return ErrWrongFormat
+}
+
+// syntaxError locates a format error inside the text given to parseLine,
+// it is turned into a ParseError once the line is known.
+type syntaxError struct {
+	offset int
+	reason string
+}
+
+func (e *syntaxError) Error() string {
+	return e.reason
+}
+
+// shift moves the offset of a syntax error found in a part of the text starting at by.
+func shift(err error, by int) error {
+	if syntax, ok := err.(*syntaxError); ok {
+		syntax.offset += by
+	}
+	return err
+}
+
+// entry is a key value pair parsed from a single line.
+type entry struct {
+	key   string
+	value string
+	// expand is set when the value holds variable references that still have to be expanded.
+	expand bool
+	// line is the number of the line the entry starts on.
+	line int
+	// bare is set for "export KEY" lines that do not assign a value.
+	bare bool
+	// valueStart and valueEnd bound the value in the line, quotes included and inline comment excluded.
+	valueStart int
+	valueEnd   int
+}
+
+// parseLine splits a trimmed line into its key and its value on the first separator,
+// so the value itself may contain '=' and ':'.
+// When expansion is set, escaped dollar signs in double quoted values are kept as "$$"
+// so they are not taken as references later.
+func parseLine(line string, expansion bool) (entry, error) {
+	rest, exported := trimExport(line)
+	prefix := len(line) - len(rest)
+
+	separator := strings.IndexAny(rest, "=:")
+	if separator == -1 {
+		if exported && isName(rest) {
+			return entry{key: rest, bare: true}, nil
+		}
+		return entry{}, &syntaxError{offset: 0, reason: "missing '=' or ':' separator"}
+	}
+
+	key := strings.TrimSpace(rest[:separator])
+	value, literal, err := parseValue(rest[separator+1:], expansion)
+	if err != nil {
+		return entry{}, shift(err, prefix+separator+1)
+	}
+
+	start, end := valueSpan(rest[separator+1:])
+
+	return entry{
+		key:        key,
+		value:      value,
+		expand:     expansion && !literal && strings.Contains(value, "$"),
+		valueStart: prefix + separator + 1 + start,
+		valueEnd:   prefix + separator + 1 + end,
+	}, nil
+}
+
+// valueSpan returns the bounds of a value that was parsed successfully,
+// quotes included and inline comment excluded.
+func valueSpan(raw string) (int, int) {
+	start := len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace))
+	if start == len(raw) {
+		return start, start
+	}
+
+	switch raw[start] {
+	case '"':
+		for i := start + 1; i < len(raw); i++ {
+			switch raw[i] {
+			case '\\':
+				i++
+			case '"':
+				return start, i + 1
+			}
+		}
+	case '\'', '`':
+		return start, start + 1 + strings.IndexByte(raw[start+1:], raw[start]) + 1
+	}
+
+	end := len(strings.TrimRightFunc(stripComment(raw), unicode.IsSpace))
+	return min(start, end), end
+}
+
+// trimExport strips the "export" prefix of lines shared with shell scripts
+// and reports whether it was found. The prefix must be followed by whitespace and a key,
+// so "export = value" defines the key export.
+func trimExport(line string) (string, bool) {
+	rest, found := strings.CutPrefix(line, "export")
+	if !found || len(rest) == 0 || (rest[0] != ' ' && rest[0] != '\t') {
+		return line, false
+	}
+
+	rest = strings.TrimLeft(rest, " \t")
+	if len(rest) == 0 || rest[0] == '=' || rest[0] == ':' {
+		return line, false
+	}
+	return rest, true
+}
+
+// isUnterminated reports whether the line opens a double quoted value that is not closed on the same line.
+func isUnterminated(line string) bool {
+	separator := strings.IndexAny(line, "=:")
+	if separator == -1 {
+		return false
+	}
+
+	raw := strings.TrimSpace(line[separator+1:])
+	if len(raw) == 0 || raw[0] != '"' {
+		return false
+	}
+
+	for i := 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"':
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseValue strips the quotes and the inline comment around a value and processes its escape sequences.
+// It reports whether the value was quoted literally and must not be expanded.
+func parseValue(raw string, expansion bool) (string, bool, error) {
+	trimmed := strings.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return trimmed, false, nil
+	}
+
+	lead := len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace))
+	switch trimmed[0] {
+	case '"':
+		value, err := parseDoubleQuoted(trimmed, expansion)
+		return value, false, shift(err, lead)
+	case '\'', '`':
+		value, err := parseLiteralQuoted(trimmed)
+		return value, true, shift(err, lead)
+	}
+
+	return strings.TrimSpace(stripComment(raw)), false, nil
+}
+
+// stripComment removes the comment from an unquoted value, a comment starts with a '#'
+// preceded by whitespace so URL fragments and colors like #fff are kept.
+func stripComment(raw string) string {
+	for i := 1; i < len(raw); i++ {
+		if raw[i] == '#' && (raw[i-1] == ' ' || raw[i-1] == '\t') {
+			return raw[:i]
+		}
+	}
+	return raw
+}
+
+// parseDoubleQuoted parses a value surrounded by double quotes, processing escape sequences.
+func parseDoubleQuoted(raw string, expansion bool) (string, error) {
+	var value strings.Builder
+
+	for i := 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '"':
+			if trailing := trailingText(raw[i+1:]); trailing != -1 {
+				return "", &syntaxError{offset: i + 1 + trailing, reason: "unexpected text after closing quote"}
+			}
+			return value.String(), nil
+		case '\\':
+			if i+1 == len(raw) {
+				return "", &syntaxError{offset: 0, reason: "unterminated double quoted value"}
+			}
+			i++
+			if raw[i] == '$' && expansion {
+				value.WriteString("$$")
+				continue
+			}
+			value.WriteString(unescape(raw[i]))
+		default:
+			value.WriteByte(raw[i])
+		}
+	}
+
+	return "", &syntaxError{offset: 0, reason: "unterminated double quoted value"}
+}
+
+// parseLiteralQuoted parses a value surrounded by single quotes or backticks, keeping its content as is.
+func parseLiteralQuoted(raw string) (string, error) {
+	closing := strings.IndexByte(raw[1:], raw[0])
+	if closing == -1 {
+		return "", &syntaxError{offset: 0, reason: fmt.Sprintf("unterminated %c quoted value", raw[0])}
+	}
+	closing++
+
+	if trailing := trailingText(raw[closing+1:]); trailing != -1 {
+		return "", &syntaxError{offset: closing + 1 + trailing, reason: "unexpected text after closing quote"}
+	}
+
+	return raw[1:closing], nil
+}
+
+// unescape returns the character represented by an escape sequence inside double quotes.
+func unescape(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case '"', '\\', '\'', '`', '$':
+		return string(c)
+	}
+	return "\\" + string(c)
+}
+
+// trailingText returns the offset of the first character following a closing quote
+// that is neither whitespace nor the start of a comment, or -1.
+func trailingText(s string) int {
+	trimmed := strings.TrimLeft(s, " \t")
+	if len(trimmed) == 0 || trimmed[0] == '#' {
+		return -1
+	}
+	return len(s) - len(trimmed)
+}
@@ -0,0 +1,64 @@
+package dotenv
+
+import (
+	"fmt"
+)
+
+// Precedence decides which value is kept when LoadFromFiles finds a key in several files.
+type Precedence int
+
+const (
+	// LastWins keeps the value of the last file defining the key.
+	LastWins Precedence = iota
+	// FirstWins keeps the value of the first file defining the key, like the Node and Ruby dotenv libraries.
+	FirstWins
+	// ErrorOnConflict fails with ErrAlreadyExists when a key is defined in several files.
+	ErrorOnConflict
+)
+
+// Override records a key defined in several files.
+type Override struct {
+	Key string
+	// File is the file whose value is kept.
+	File string
+	// OverriddenFile is the file whose value was discarded.
+	OverriddenFile string
+}
+
+// Overrides returns the keys that were defined in several files by the last load, in the order they were found.
+func (env *EnvContent) Overrides() []Override {
+	return env.overrides
+}
+
+// reset empties the content before a new load.
+func (env *EnvContent) reset() {
+	env.keyValuePairs = make(map[string]string)
+	env.sources = make(map[string]string)
+	env.overrides = nil
+	env.order = nil
+	env.document = nil
+	env.newline = ""
+}
+
+// assign stores a parsed entry following the precedence between files,
+// and reports whether the value was kept.
+// Keys defined several times in the same file always keep their last value.
+func (env *EnvContent) assign(parsed entry, fileName string) (bool, error) {
+	previous, found := env.sources[parsed.key]
+	if found && previous != fileName {
+		switch env.Precedence {
+		case FirstWins:
+			env.overrides = append(env.overrides, Override{Key: parsed.key, File: previous, OverriddenFile: fileName})
+			return false, nil
+		case ErrorOnConflict:
+			return false, fmt.Errorf("%w: line %d: %s is already defined in %s",
+				ErrAlreadyExists, parsed.line, parsed.key, previous)
+		default:
+			env.overrides = append(env.overrides, Override{Key: parsed.key, File: fileName, OverriddenFile: previous})
+		}
+	}
+
+	env.sources[parsed.key] = fileName
+	env.store(parsed.key, parsed.value)
+	return true, nil
+}
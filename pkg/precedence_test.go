@@ -0,0 +1,103 @@
+package dotenv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type PrecedenceTestCase struct {
+	desc              string
+	precedence        Precedence
+	paths             []string
+	expectedError     error
+	expectedMap       map[string]string
+	expectedOverrides []Override
+}
+
+func TestENV_Precedence(t *testing.T) {
+	testCases := []PrecedenceTestCase{
+		{
+			desc:          "Last file wins",
+			precedence:    LastWins,
+			paths:         []string{"testdata/test_09.txt", "testdata/test_21.txt"},
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"key1": "override",
+				"key2": "value2",
+				"key3": "value3",
+				"key4": "value4",
+				"key5": "value5",
+			},
+			expectedOverrides: []Override{
+				{Key: "key1", File: "testdata/test_21.txt", OverriddenFile: "testdata/test_09.txt"},
+			},
+		},
+		{
+			desc:          "First file wins",
+			precedence:    FirstWins,
+			paths:         []string{"testdata/test_09.txt", "testdata/test_21.txt"},
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"key1": "value1",
+				"key2": "value2",
+				"key3": "value3",
+				"key4": "value4",
+				"key5": "value5",
+			},
+			expectedOverrides: []Override{
+				{Key: "key1", File: "testdata/test_09.txt", OverriddenFile: "testdata/test_21.txt"},
+			},
+		},
+		{
+			desc:          "Conflicting keys fail",
+			precedence:    ErrorOnConflict,
+			paths:         []string{"testdata/test_09.txt", "testdata/test_21.txt"},
+			expectedError: ErrAlreadyExists,
+			expectedMap:   map[string]string{},
+		},
+		{
+			desc:          "Keys repeated in the same file are not conflicts",
+			precedence:    ErrorOnConflict,
+			paths:         []string{"testdata/test_22.txt", "testdata/test_21.txt"},
+			expectedError: nil,
+			expectedMap: map[string]string{
+				"key":  "second",
+				"key1": "override",
+				"key5": "value5",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			parser := EnvContent{Precedence: test.precedence}
+			resultedMap, resultedError := parser.LoadFromFiles(test.paths)
+
+			assert.ErrorIs(t, resultedError, test.expectedError)
+			assert.Equal(t, test.expectedOverrides, parser.Overrides())
+			if !reflect.DeepEqual(test.expectedMap, resultedMap) {
+				t.Fail()
+			}
+
+		})
+	}
+}
+
+func TestENV_ConflictMessage(t *testing.T) {
+	parser := EnvContent{Precedence: ErrorOnConflict}
+	_, err := parser.LoadFromFiles([]string{"testdata/test_09.txt", "testdata/test_21.txt"})
+
+	assert.Equal(t, "testdata/test_21.txt: key value pair already exists: "+
+		"line 2: key1 is already defined in testdata/test_09.txt", err.Error())
+}
+
+func TestENV_PrecedenceExpansion(t *testing.T) {
+	parser := EnvContent{Precedence: FirstWins}
+	resultedMap, err := parser.LoadFromFiles([]string{"testdata/test_09.txt", "testdata/test_23.txt"})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "value1", resultedMap["key1"])
+	assert.Equal(t, "value1-value2", resultedMap["joined"])
+}
@@ -0,0 +1,77 @@
+package dotenv
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// saveFile replaces the content of fileName with data, following symbolic links so the link is kept.
+// When backup is set, the previous content is first saved to fileName.bak.
+func saveFile(fileName string, data []byte, backup bool) error {
+	if target, err := filepath.EvalSymlinks(fileName); err == nil {
+		fileName = target
+	}
+
+	info, err := os.Stat(fileName)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if info != nil && backup {
+		previous, err := os.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		if err := replaceFile(fileName+".bak", previous, info); err != nil {
+			return err
+		}
+	}
+
+	return replaceFile(fileName, data, info)
+}
+
+// replaceFile writes data to a temporary file in the directory of fileName, syncs it
+// and renames it over fileName. The file gets the mode and owner of info,
+// or is only readable by its owner when info is nil.
+func replaceFile(fileName string, data []byte, info fs.FileInfo) (err error) {
+	dir := filepath.Dir(fileName)
+
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(fileName)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			temp.Close()
+			os.Remove(temp.Name())
+		}
+	}()
+
+	if _, err = temp.Write(data); err != nil {
+		return err
+	}
+
+	if info != nil {
+		if err = temp.Chmod(info.Mode().Perm()); err != nil {
+			return err
+		}
+		if err = chown(temp, info); err != nil {
+			return err
+		}
+	}
+
+	if err = temp.Sync(); err != nil {
+		return err
+	}
+	if err = temp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(temp.Name(), fileName); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
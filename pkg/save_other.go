@@ -0,0 +1,18 @@
+//go:build !unix
+
+package dotenv
+
+import (
+	"io/fs"
+	"os"
+)
+
+// chown does nothing where files do not have a numeric owner.
+func chown(file *os.File, info fs.FileInfo) error {
+	return nil
+}
+
+// syncDir does nothing where directories can not be synced.
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,130 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SaveToFileTestCase struct {
+	desc             string
+	previous         string
+	mode             os.FileMode
+	backup           bool
+	expectedMode     os.FileMode
+	expectedBackup   string
+	expectedDocument string
+}
+
+func TestENV_SaveToFileAtomic(t *testing.T) {
+	testCases := []SaveToFileTestCase{
+		{
+			desc:             "New file",
+			expectedMode:     0o600,
+			expectedDocument: "KEY=value\n",
+		},
+		{
+			desc:             "Existing file keeps its mode",
+			previous:         "# settings\nKEY=old\n",
+			mode:             0o640,
+			expectedMode:     0o640,
+			expectedDocument: "KEY=value\n",
+		},
+		{
+			desc:             "Existing file with backup",
+			previous:         "# settings\nKEY=old\n",
+			mode:             0o600,
+			backup:           true,
+			expectedMode:     0o600,
+			expectedBackup:   "# settings\nKEY=old\n",
+			expectedDocument: "KEY=value\n",
+		},
+		{
+			desc:             "New file with backup",
+			backup:           true,
+			expectedMode:     0o600,
+			expectedDocument: "KEY=value\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			if runtime.GOOS == "windows" && test.mode&0o077 != 0 {
+				t.Skip("file modes are not supported")
+			}
+
+			dir := t.TempDir()
+			fileName := filepath.Join(dir, ".env")
+			if test.previous != "" {
+				assert.Equal(t, nil, os.WriteFile(fileName, []byte(test.previous), test.mode))
+				assert.Equal(t, nil, os.Chmod(fileName, test.mode))
+			}
+
+			parser := EnvContent{Backup: test.backup}
+			parser.Set("KEY", "value")
+			assert.Equal(t, nil, parser.SaveToFile(fileName))
+
+			document, err := os.ReadFile(fileName)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedDocument, string(document))
+
+			info, err := os.Stat(fileName)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedMode, info.Mode().Perm())
+
+			backup, err := os.ReadFile(fileName + ".bak")
+			if test.expectedBackup == "" {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+			} else {
+				assert.Equal(t, test.expectedBackup, string(backup))
+				info, err := os.Stat(fileName + ".bak")
+				assert.Equal(t, nil, err)
+				assert.Equal(t, test.expectedMode, info.Mode().Perm())
+			}
+
+			entries, err := os.ReadDir(dir)
+			assert.Equal(t, nil, err)
+			expectedEntries := 1
+			if test.expectedBackup != "" {
+				expectedEntries = 2
+			}
+			assert.Len(t, entries, expectedEntries)
+		})
+	}
+}
+
+func TestENV_SaveToFileSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "shared.env")
+	link := filepath.Join(dir, ".env")
+
+	assert.Equal(t, nil, os.WriteFile(target, []byte("KEY=old\n"), 0o600))
+	if err := os.Symlink(target, link); err != nil {
+		t.Skip("symbolic links are not supported")
+	}
+
+	parser := EnvContent{}
+	_, err := parser.LoadFromFile(link)
+	assert.Equal(t, nil, err)
+	parser.Set("KEY", "value")
+	assert.Equal(t, nil, parser.SaveToFile(link))
+
+	info, err := os.Lstat(link)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, os.ModeSymlink, info.Mode()&os.ModeSymlink)
+
+	document, err := os.ReadFile(target)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "KEY=value\n", string(document))
+}
+
+func TestENV_SaveToFileMissingDirectory(t *testing.T) {
+	parser := EnvContent{}
+	parser.Set("KEY", "value")
+
+	err := parser.SaveToFile(filepath.Join(t.TempDir(), "missing", ".env"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,38 @@
+//go:build unix
+
+package dotenv
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// chown gives file the owner and group of info when they differ from its own.
+func chown(file *os.File, info fs.FileInfo) error {
+	owner, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	current, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if stat, ok := current.Sys().(*syscall.Stat_t); ok && stat.Uid == owner.Uid && stat.Gid == owner.Gid {
+		return nil
+	}
+
+	return file.Chown(int(owner.Uid), int(owner.Gid))
+}
+
+// syncDir makes the rename of a file in dir durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
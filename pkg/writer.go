@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidKey is returned when a key can not be written in a form that loads back to the same key.
+var ErrInvalidKey = errors.New("key can not be written to a .env file")
+
+// WriteTo writes the key value pairs to w as a .env document, quoting and escaping values
+// so LoadFromString gives back the same pairs.
+// After loading a single file or string, its comments and layout are kept: only the lines
+// of the changed keys are rewritten and new keys are added at the end. Once a key changed,
+// values expanded from references are written expanded so they load back unchanged.
+// Keys are written in the order they were first defined.
+func (env *EnvContent) WriteTo(w io.Writer) (int64, error) {
+	var document bytes.Buffer
+	written := env.writeDocument(&document)
+
+	for _, key := range env.order {
+		if written[key] {
+			continue
+		}
+		if err := checkKey(key); err != nil {
+			return 0, err
+		}
+
+		if document.Len() > 0 && !bytes.HasSuffix(document.Bytes(), []byte("\n")) {
+			document.WriteString(env.lineEnding())
+		}
+		document.WriteString(key + "=" + quoteValue(env.keyValuePairs[key]) + env.lineEnding())
+	}
+
+	return document.WriteTo(w)
+}
+
+// SaveToFile writes the key value pairs to a .env file, replacing its content atomically:
+// a crash while saving leaves either the previous or the new content on disk.
+// An existing file keeps its mode and owner, a new file is only readable by its owner
+// since .env files usually hold secrets.
+func (env *EnvContent) SaveToFile(fileName string) error {
+	var document bytes.Buffer
+	if _, err := env.WriteTo(&document); err != nil {
+		return err
+	}
+
+	return saveFile(fileName, document.Bytes(), env.Backup)
+}
+
+// checkKey makes sure a key is parsed back as itself.
+func checkKey(key string) error {
+	_, exported := trimExport(key)
+	if key == "" || key != strings.TrimSpace(key) || strings.ContainsAny(key, "=:\n\r") ||
+		key[0] == '#' || exported {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package dotenv
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type WriteToTestCase struct {
+	desc             string
+	input            map[string]string
+	expectedError    error
+	expectedDocument string
+}
+
+func TestENV_WriteTo(t *testing.T) {
+	testCases := []WriteToTestCase{
+		{
+			desc:             "Empty content",
+			input:            map[string]string{},
+			expectedError:    nil,
+			expectedDocument: "",
+		},
+		{
+			desc: "Values that need quotes",
+			input: map[string]string{
+				"PLAIN":   "value",
+				"EMPTY":   "",
+				"SPACES":  "hello world",
+				"COMMENT": "value # not a comment",
+				"QUOTES":  `say "hi"`,
+				"SINGLE":  "it's",
+				"DOLLAR":  "pa$word",
+				"TLS_KEY": testPEM,
+				"URL":     "postgres://u:p@h/db?sslmode=disable",
+				"my key":  "value",
+			},
+			expectedError: nil,
+			expectedDocument: "COMMENT='value # not a comment'\n" +
+				"DOLLAR='pa$word'\n" +
+				"EMPTY=\n" +
+				"PLAIN=value\n" +
+				"QUOTES='say \"hi\"'\n" +
+				"SINGLE=\"it's\"\n" +
+				"SPACES='hello world'\n" +
+				"TLS_KEY=\"" + strings.ReplaceAll(testPEM, "\n", `\n`) + "\"\n" +
+				"URL=postgres://u:p@h/db?sslmode=disable\n" +
+				"my key=value\n",
+		},
+		{
+			desc:          "Key with separator",
+			input:         map[string]string{"KEY=NAME": "value"},
+			expectedError: ErrInvalidKey,
+		},
+		{
+			desc:          "Key with export prefix",
+			input:         map[string]string{"export KEY": "value"},
+			expectedError: ErrInvalidKey,
+		},
+		{
+			desc:          "Empty key",
+			input:         map[string]string{"": "value"},
+			expectedError: ErrInvalidKey,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			keys := make([]string, 0, len(test.input))
+			for key := range test.input {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			parser := EnvContent{}
+			for _, key := range keys {
+				parser.Set(key, test.input[key])
+			}
+
+			var document bytes.Buffer
+			written, resultedError := parser.WriteTo(&document)
+
+			assert.ErrorIs(t, resultedError, test.expectedError)
+			if test.expectedError != nil {
+				return
+			}
+
+			assert.Equal(t, test.expectedDocument, document.String())
+			assert.Equal(t, int64(document.Len()), written)
+
+			loaded := EnvContent{}
+			_, _ = loaded.LoadFromString(document.String())
+			resultedMap, _ := loaded.GetEnv()
+			assert.Equal(t, test.input, resultedMap)
+		})
+	}
+}
+
+func TestENV_SaveToFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), ".env")
+
+	parser := EnvContent{}
+	_, _ = parser.LoadFromFile("testdata/test_20.txt")
+	parser.Set("GREETING", "hello world")
+
+	assert.Equal(t, nil, parser.SaveToFile(fileName))
+
+	info, err := os.Stat(fileName)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	loaded := EnvContent{}
+	resultedMap, err := loaded.LoadFromFile(fileName)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]string{
+		"TLS_CERT_PATH": "/etc/tls/cert.pem",
+		"TLS_KEY":       testPEM,
+		"TLS_ENABLED":   "true",
+		"GREETING":      "hello world",
+	}, resultedMap)
+}
+
+func TestENV_WriteToOrder(t *testing.T) {
+	parser := EnvContent{}
+	_, err := parser.LoadFromFiles([]string{"testdata/test_24.txt", "testdata/test_09.txt"})
+	assert.Equal(t, nil, err)
+	parser.Set("NEW", "value")
+
+	var document bytes.Buffer
+	_, err = parser.WriteTo(&document)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "DOTENV_TEST_SET_B=from-file\n"+
+		"DOTENV_TEST_SET_A=from-file\n"+
+		"key1=value1\n"+
+		"key2=value2\n"+
+		"key3=value3\n"+
+		"key4=value4\n"+
+		"NEW=value\n", document.String())
+}